@@ -0,0 +1,74 @@
+// Command accrual-sim runs an in-memory accrual system for local development,
+// for example with `gophermart dev -accrual-binary bin/accrual-sim`.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"math"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/with0p/gophermart/internal/accrualsim"
+	"github.com/with0p/gophermart/internal/logger"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func main() {
+	address := flag.String("a", "localhost:8081", "address to listen on")
+	// accepted for compatibility with the accrual binary; the simulator keeps
+	// everything in memory
+	flag.String("d", "", "ignored")
+	rpm := flag.Int("rpm", 0, "order lookups allowed per minute, 0 for unlimited")
+	minDelay := flag.Duration("delay-min", time.Second, "minimum time spent in each of REGISTERED and PROCESSING")
+	maxDelay := flag.Duration("delay-max", 5*time.Second, "maximum time spent in each of REGISTERED and PROCESSING")
+	invalidRate := flag.Float64("invalid-rate", 0.1, "share of orders that end up INVALID")
+	autoRegister := flag.Bool("auto-register", true, "register unknown valid order numbers on lookup")
+	autoAccrualMax := flag.Float64("auto-accrual-max", 500, "maximum accrual of auto-registered orders")
+	seed := flag.Int64("seed", 0, "random seed, 0 for a random one")
+	flag.Parse()
+
+	simulator := accrualsim.NewSimulator(accrualsim.Config{
+		RequestsPerMinute: *rpm,
+		MinDelay:          *minDelay,
+		MaxDelay:          *maxDelay,
+		InvalidRate:       *invalidRate,
+		AutoRegister:      *autoRegister,
+		AutoAccrualMax:    models.Amount(math.Round(*autoAccrualMax * 100)),
+		Seed:              *seed,
+	})
+
+	server := &http.Server{
+		Addr:              *address,
+		Handler:           simulator.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(err)
+			select {
+			case stop <- syscall.SIGTERM:
+			default:
+			}
+		}
+	}()
+	logger.Info("Listening", "address", *address)
+
+	<-stop
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error(err)
+	}
+	logger.Info("Server is stopped")
+}
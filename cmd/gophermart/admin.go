@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/with0p/gophermart/internal/models"
+	"github.com/with0p/gophermart/internal/storage"
+)
+
+const adminUsage = "usage: gophermart admin grant|revoke <login>"
+
+// runAdmin grants or revokes the admin role, which is how the first admin
+// gets created.
+func runAdmin(ctx context.Context, db *sql.DB, args []string) error {
+	if len(args) != 2 {
+		return errors.New(adminUsage)
+	}
+
+	currentStorage, err := storage.NewStorageDB(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	user, err := currentStorage.GetUserInfo(ctx, args[1])
+	if err != nil {
+		return err
+	}
+
+	roles := []string{}
+	for _, role := range user.Roles {
+		if role != models.RoleAdmin {
+			roles = append(roles, role)
+		}
+	}
+
+	switch args[0] {
+	case "grant":
+		roles = append(roles, models.RoleAdmin)
+	case "revoke":
+	default:
+		return errors.New(adminUsage)
+	}
+
+	return currentStorage.SetUserRoles(ctx, user.Login, roles)
+}
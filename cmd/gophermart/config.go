@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/with0p/gophermart/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+const configUsage = "usage: gophermart config print"
+
+// runConfig prints the effective configuration with secrets redacted,
+// followed by whatever would stop the server from starting.
+func runConfig(conf *config.Config, args []string) error {
+	if len(args) != 1 || args[0] != "print" {
+		return errors.New(configUsage)
+	}
+
+	encoder := yaml.NewEncoder(os.Stdout)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(conf.Redacted()); err != nil {
+		return err
+	}
+
+	if err := conf.ValidateServer(); err != nil {
+		printConfigErrors(err)
+	}
+	return nil
+}
+
+func printConfigErrors(err error) {
+	fmt.Fprintln(os.Stderr, "invalid configuration:")
+	var joined interface{ Unwrap() []error }
+	if errors.As(err, &joined) {
+		for _, problem := range joined.Unwrap() {
+			fmt.Fprintln(os.Stderr, "  "+problem.Error())
+		}
+		return
+	}
+	fmt.Fprintln(os.Stderr, "  "+err.Error())
+}
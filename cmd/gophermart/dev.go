@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/with0p/gophermart/internal/config"
+	"github.com/with0p/gophermart/internal/supervisor"
+)
+
+const devUsage = "usage: gophermart dev [-accrual-binary path]"
+
+const defaultDevAccrualAddress = "localhost:8081"
+
+type devOptions struct {
+	accrualBinary string
+}
+
+// parseDev prepares `gophermart dev`: the server runs as usual next to a
+// local accrual binary that is restarted whenever it exits.
+func parseDev(conf *config.Config, args []string) (*devOptions, error) {
+	flags := flag.NewFlagSet("gophermart dev", flag.ContinueOnError)
+	binary := flags.String("accrual-binary", defaultAccrualBinary(), "accrual binary to run")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	if flags.NArg() > 0 {
+		return nil, errors.New(devUsage)
+	}
+
+	if _, err := os.Stat(*binary); err != nil {
+		return nil, fmt.Errorf("accrual binary: %w", err)
+	}
+
+	if conf.Accrual.Address == "" {
+		conf.Accrual.Address = defaultDevAccrualAddress
+	}
+
+	return &devOptions{accrualBinary: *binary}, nil
+}
+
+// defaultAccrualBinary picks the accrual build for this OS and architecture
+// from cmd/accrual.
+func defaultAccrualBinary() string {
+	name := fmt.Sprintf("accrual_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return filepath.Join("cmd", "accrual", name)
+}
+
+// superviseAccrual starts the accrual binary and returns a function that
+// stops it and waits for it to exit.
+func (d *devOptions) superviseAccrual(conf *config.Config) func() {
+	args := []string{"-a", listenAddress(conf.Accrual.Address)}
+	if conf.Accrual.DatabaseURI != "" {
+		args = append(args, "-d", conf.Accrual.DatabaseURI)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		supervisor.Run(ctx, supervisor.Config{
+			Name: "accrual",
+			Path: d.accrualBinary,
+			Args: args,
+		})
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}
+}
+
+// listenAddress turns the address gophermart calls into one the accrual
+// binary can listen on.
+func listenAddress(address string) string {
+	if !strings.Contains(address, "://") {
+		return address
+	}
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return address
+	}
+	return parsed.Host
+}
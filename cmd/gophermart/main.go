@@ -3,34 +3,112 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
 	"net/http"
 	"os"
-	"os/exec"
 	"os/signal"
-	"sync"
 	"syscall"
 	"time"
 
+	"github.com/with0p/gophermart/internal/accrual"
+	"github.com/with0p/gophermart/internal/auth"
 	"github.com/with0p/gophermart/internal/config"
 	"github.com/with0p/gophermart/internal/handlers"
 	"github.com/with0p/gophermart/internal/logger"
-	"github.com/with0p/gophermart/internal/models"
+	"github.com/with0p/gophermart/internal/metrics"
 	"github.com/with0p/gophermart/internal/service"
 	"github.com/with0p/gophermart/internal/storage"
+	"github.com/with0p/gophermart/internal/tracing"
+	"github.com/with0p/gophermart/internal/utils"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	_ "time/tzdata"
 )
 
 func main() {
-	config := config.GetConfig()
+	config, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if len(args) > 0 && args[0] == "config" {
+		if err := runConfig(config, args[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
 
-	db, dbErr := sql.Open("pgx", config.DataBaseAddress)
+	var dev *devOptions
+	if len(args) > 0 && args[0] == "dev" {
+		dev, err = parseDev(config, args[1:])
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		args = nil
+	}
+
+	// dev mode may run with an ephemeral signing key; commands need none
+	validate := config.ValidateServer
+	if len(args) > 0 || dev != nil {
+		validate = config.Validate
+	}
+	if err := validate(); err != nil {
+		printConfigErrors(err)
+		os.Exit(2)
+	}
+
+	if err := logger.Setup(logger.Config{Level: config.Log.Level, Format: config.Log.Format}); err != nil {
+		logger.Error(err)
+		os.Exit(2)
+	}
+
+	db, dbErr := openDB(config.Database)
 	if dbErr != nil {
 		logger.Error(dbErr)
 		return
 	}
 	defer db.Close()
 
+	if len(args) > 0 {
+		var err error
+		switch args[0] {
+		case "migrate":
+			err = runMigrate(context.Background(), db, args[1:])
+		case "admin":
+			err = runAdmin(context.Background(), db, args[1:])
+		default:
+			logger.Error(fmt.Errorf("unknown command %q", args[0]))
+			os.Exit(2)
+		}
+		if err != nil {
+			logger.Error(err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    config.Tracing.Exporter,
+		ServiceName: "gophermart",
+	})
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+
 	ctx, cancelInitDB := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancelInitDB()
 
@@ -39,51 +117,95 @@ func main() {
 		logger.Error(err)
 		return
 	}
+	if err := errors.Join(
+		metrics.RegisterDB(metrics.Registry, db),
+		metrics.RegisterQueueDepth(metrics.Registry, storage.CountOrderJobs),
+	); err != nil {
+		logger.Error(err)
+		return
+	}
 
-	queue := make(chan models.OrderID, 10)
-	service := service.NewServiceGophermart(storage)
-	handler := handlers.NewHandlerUserAPI(&service, queue)
+	accrualClient := accrual.NewHTTPClient(accrual.ClientConfig{
+		BaseURL: config.Accrual.Address,
+		Timeout: config.Accrual.Timeout,
+	})
+	service := service.NewServiceGophermart(storage, accrualClient, utils.NewArgon2idHasher(), service.Options{
+		AccrualWorkers:  config.Accrual.Workers,
+		QueueSize:       config.Accrual.QueueSize,
+		MaxJobAttempts:  config.Accrual.MaxAttempts,
+		WebhookDelivery: config.Features.WebhookDelivery,
+	})
+	keys, err := loadJWTKeys(config.Auth)
+	if err != nil {
+		logger.Error(err)
+		return
+	}
+	authenticator := auth.NewAuthenticator(keys, storage, auth.Options{
+		Precedence:      auth.CredentialSource(config.Auth.Precedence),
+		CSRFProtection:  config.Auth.CSRFProtection,
+		AccessTokenTTL:  config.Auth.AccessTokenTTL,
+		RefreshTokenTTL: config.Auth.RefreshTokenTTL,
+	})
+	handler := handlers.NewHandlerUserAPI(&service, authenticator)
+	adminHandler := handlers.NewHandlerAdminAPI(&service, authenticator)
+	healthHandler := handlers.NewHandlerHealth(&service)
 	router := handler.GetHandlerUserAPIRouter()
-	server := &http.Server{Addr: config.BaseURL, Handler: router}
+	router.Mount("/api/admin", adminHandler.GetHandlerAdminAPIRouter())
+	if config.Features.Metrics {
+		router.Handle("/metrics", metrics.Handler())
+	}
+	router.Get("/healthz", healthHandler.Liveness)
+	router.Get("/readyz", healthHandler.Readiness)
+	// request contexts are cancelled on shutdown so that event streams end
+	// instead of holding Shutdown until its timeout
+	serverCtx, cancelServerCtx := context.WithCancel(context.Background())
+	server := &http.Server{
+		Addr:              config.Server.Address,
+		Handler:           router,
+		ReadHeaderTimeout: config.Server.ReadHeaderTimeout,
+		ReadTimeout:       config.Server.ReadTimeout,
+		IdleTimeout:       config.Server.IdleTimeout,
+		BaseContext:       func(net.Listener) context.Context { return serverCtx },
+	}
+	server.RegisterOnShutdown(cancelServerCtx)
 
-	//run accrual
-	var accrualCmd *exec.Cmd
-	var wg sync.WaitGroup
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		accrualCmd = startAccrualService(config.AccrualURL)
-	}()
+	//run accrual in dev mode
+	stopAccrual := func() {}
+	if dev != nil {
+		stopAccrual = dev.superviseAccrual(config)
+	}
 
 	//start processing routine
-	go service.ProcessOrders(queue, config.AccrualURL)
+	if err := service.Start(context.Background()); err != nil {
+		logger.Error(err)
+		os.Exit(1)
+	}
 
-	//run periodic queue feed to process unfinished orders
-	go func() {
-		interval := time.Minute
-		for {
-			time.Sleep(interval)
-			service.FeedQueue(queue)
-		}
-	}()
+	//server shutdown
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
 
 	//run gophermart
 	go func() {
 		err := server.ListenAndServe()
-		if err != nil {
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logger.Error(err)
-			return
+			select {
+			case stop <- syscall.SIGTERM:
+			default:
+			}
 		}
 	}()
-
-	//server shutdown
-	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	logger.Info("Listening", "address", config.Server.Address)
 
 	<-stop
 	logger.Info("Starting to shutting down...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	// fail readiness first and give load balancers time to notice
+	healthHandler.Drain()
+	time.Sleep(config.Server.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.Server.ShutdownTimeout)
 	defer cancel()
 
 	if err := server.Shutdown(ctx); err != nil {
@@ -92,35 +214,50 @@ func main() {
 
 	logger.Info("Server is stopped")
 
-	if accrualCmd != nil {
-		if err := accrualCmd.Process.Signal(syscall.SIGTERM); err != nil {
-			logger.Error(err)
-		}
-
-		if err := accrualCmd.Wait(); err != nil {
-			logger.Error(err)
-		}
-		logger.Info("Accrual service stopped.")
+	// no new orders arrive once the server is down; let the workers finish
+	// the jobs they hold before the accrual system goes away
+	workersCtx, cancelWorkers := context.WithTimeout(context.Background(), config.Server.ShutdownTimeout)
+	defer cancelWorkers()
+	stopErr := service.Stop(workersCtx)
+	if stopErr != nil {
+		logger.Error(stopErr)
 	}
 
-	close(queue)
-	wg.Wait()
+	stopAccrual()
+
+	if err := shutdownTracing(ctx); err != nil {
+		logger.Error(err)
+	}
 
 	logger.Info("All services stopped.")
+	if stopErr != nil {
+		os.Exit(1)
+	}
 }
 
-func startAccrualService(url string) *exec.Cmd {
-	cmd := exec.Command("./accrual_darwin_arm64", "-a", url)
-	cmd.Dir = "cmd/accrual"
+// openDB opens the pool through pgx so that every statement is traced.
+func openDB(conf config.DatabaseConfig) (*sql.DB, error) {
+	connConfig, err := pgx.ParseConfig(conf.URI)
+	if err != nil {
+		return nil, err
+	}
+	connConfig.Tracer = tracing.QueryTracer{}
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	db := stdlib.OpenDB(*connConfig)
+	db.SetMaxOpenConns(conf.MaxOpenConns)
+	db.SetMaxIdleConns(conf.MaxIdleConns)
+	db.SetConnMaxLifetime(conf.ConnMaxLifetime)
+	return db, nil
+}
 
-	if err := cmd.Start(); err != nil {
-		logger.Error(err)
-		return nil
+func loadJWTKeys(conf config.AuthConfig) (*auth.KeySet, error) {
+	switch {
+	case conf.JWTKeysFile != "":
+		return auth.LoadKeySet(conf.JWTKeysFile)
+	case conf.JWTSecret != "":
+		return auth.NewSecretKeySet(conf.JWTSecret)
+	default:
+		logger.Warn("No JWT keys configured, using an ephemeral key: tokens will not survive a restart")
+		return auth.NewRandomKeySet()
 	}
-
-	logger.Info("Accrual is running")
-	return cmd
 }
@@ -0,0 +1,119 @@
+// Package accrualtest provides an in-process fake of the accrual system for
+// tests. Responses are scripted per order number and replayed in order; the
+// last scripted response for an order is repeated once the script runs out.
+package accrualtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/with0p/gophermart/internal/models"
+)
+
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+	Order      *models.OrderExternalData
+}
+
+func OK(status string, accrual models.Amount) Response {
+	return Response{
+		StatusCode: http.StatusOK,
+		Order:      &models.OrderExternalData{Status: status, Accrual: accrual},
+	}
+}
+
+func NotRegistered() Response {
+	return Response{StatusCode: http.StatusNoContent}
+}
+
+func TooManyRequests(retryAfterSeconds int, requestsPerMinute int) Response {
+	return Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{"Retry-After": []string{strconv.Itoa(retryAfterSeconds)}},
+		Body:       "No more than " + strconv.Itoa(requestsPerMinute) + " requests per minute allowed",
+	}
+}
+
+func InternalError() Response {
+	return Response{StatusCode: http.StatusInternalServerError}
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	scripts  map[models.OrderID][]Response
+	requests map[models.OrderID]int
+}
+
+func NewServer() *Server {
+	s := &Server{
+		scripts:  make(map[models.OrderID][]Response),
+		requests: make(map[models.OrderID]int),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// Script appends responses for the given order.
+func (s *Server) Script(orderID models.OrderID, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.scripts[orderID] = append(s.scripts[orderID], responses...)
+}
+
+func (s *Server) Requests(orderID models.OrderID) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[orderID]
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || !strings.HasPrefix(r.URL.Path, "/api/orders/") {
+		http.NotFound(w, r)
+		return
+	}
+	orderID := models.OrderID(strings.TrimPrefix(r.URL.Path, "/api/orders/"))
+
+	resp := s.next(orderID)
+	for key, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	if resp.Order != nil {
+		order := *resp.Order
+		order.Order = string(orderID)
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(resp.StatusCode)
+		json.NewEncoder(w).Encode(order)
+		return
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write([]byte(resp.Body))
+}
+
+func (s *Server) next(orderID models.OrderID) Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[orderID]++
+
+	script := s.scripts[orderID]
+	if len(script) == 0 {
+		return NotRegistered()
+	}
+	resp := script[0]
+	if len(script) > 1 {
+		s.scripts[orderID] = script[1:]
+	}
+	return resp
+}
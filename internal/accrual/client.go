@@ -0,0 +1,27 @@
+package accrual
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+type Client interface {
+	GetOrder(ctx context.Context, orderID models.OrderID) (*models.OrderExternalData, error)
+}
+
+type RateLimitError struct {
+	RetryAfter        time.Duration
+	RequestsPerMinute int
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: retry after %s", customerror.ErrTooManyRequests, e.RetryAfter)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return customerror.ErrTooManyRequests
+}
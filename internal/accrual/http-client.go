@@ -0,0 +1,132 @@
+package accrual
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/metrics"
+	"github.com/with0p/gophermart/internal/models"
+	"github.com/with0p/gophermart/internal/tracing"
+)
+
+const (
+	defaultTimeout    = 10 * time.Second
+	defaultRetryAfter = 60 * time.Second
+)
+
+var rateLimitPattern = regexp.MustCompile(`(?i)no more than (\d+) requests per minute`)
+
+type ClientConfig struct {
+	BaseURL   string
+	Timeout   time.Duration
+	Transport http.RoundTripper
+}
+
+type HTTPClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPClient(config ClientConfig) *HTTPClient {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	transport := config.Transport
+	if transport == nil {
+		defaultTransport := http.DefaultTransport.(*http.Transport).Clone()
+		defaultTransport.MaxIdleConnsPerHost = 16
+		transport = defaultTransport
+	}
+
+	baseURL := strings.TrimRight(config.BaseURL, "/")
+	if baseURL != "" && !strings.Contains(baseURL, "://") {
+		baseURL = "http://" + baseURL
+	}
+
+	return &HTTPClient{
+		baseURL: baseURL,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: tracing.NewTransport(transport),
+		},
+	}
+}
+
+func (c *HTTPClient) GetOrder(ctx context.Context, orderID models.OrderID) (*models.OrderExternalData, error) {
+	orderURL := fmt.Sprintf("%s/api/orders/%s", c.baseURL, url.PathEscape(string(orderID)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, orderURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		metrics.AccrualRequests.WithLabelValues("error").Inc()
+		return nil, err
+	}
+	defer resp.Body.Close()
+	metrics.AccrualRequests.WithLabelValues(accrualOutcome(resp.StatusCode)).Inc()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case resp.StatusCode == http.StatusNoContent:
+		return nil, customerror.ErrOrderNotRegistered
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return nil, parseRateLimit(resp.Header.Get("Retry-After"), body)
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return nil, fmt.Errorf("%w: status %d", customerror.ErrAccrualUnavailable, resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("not accepted, status %d", resp.StatusCode)
+	}
+
+	var orderData models.OrderExternalData
+	err = json.Unmarshal(body, &orderData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderData, nil
+}
+
+func accrualOutcome(statusCode int) string {
+	switch {
+	case statusCode == http.StatusOK, statusCode == http.StatusNoContent, statusCode == http.StatusTooManyRequests:
+		return strconv.Itoa(statusCode)
+	case statusCode >= http.StatusInternalServerError:
+		return "5xx"
+	default:
+		return "other"
+	}
+}
+
+func parseRateLimit(retryAfterHeader string, body []byte) *RateLimitError {
+	rateLimitErr := &RateLimitError{RetryAfter: defaultRetryAfter}
+
+	if seconds, err := strconv.Atoi(strings.TrimSpace(retryAfterHeader)); err == nil && seconds >= 0 {
+		rateLimitErr.RetryAfter = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(retryAfterHeader); err == nil {
+		rateLimitErr.RetryAfter = time.Until(date)
+	}
+
+	if match := rateLimitPattern.FindSubmatch(body); match != nil {
+		rateLimitErr.RequestsPerMinute, _ = strconv.Atoi(string(match[1]))
+	}
+
+	return rateLimitErr
+}
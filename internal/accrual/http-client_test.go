@@ -0,0 +1,74 @@
+package accrual
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/with0p/gophermart/internal/accrual/accrualtest"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestGetOrder_Success(t *testing.T) {
+	server := accrualtest.NewServer()
+	defer server.Close()
+	server.Script("12345678903", accrualtest.OK("PROCESSED", 72998))
+
+	client := NewHTTPClient(ClientConfig{BaseURL: server.URL})
+	order, err := client.GetOrder(context.Background(), "12345678903")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "12345678903", order.Order)
+	assert.Equal(t, "PROCESSED", order.Status)
+	assert.Equal(t, models.Amount(72998), order.Accrual)
+}
+
+func TestGetOrder_NotRegistered(t *testing.T) {
+	server := accrualtest.NewServer()
+	defer server.Close()
+
+	client := NewHTTPClient(ClientConfig{BaseURL: server.URL})
+	_, err := client.GetOrder(context.Background(), "12345678903")
+
+	assert.ErrorIs(t, err, customerror.ErrOrderNotRegistered)
+}
+
+func TestGetOrder_TooManyRequests(t *testing.T) {
+	server := accrualtest.NewServer()
+	defer server.Close()
+	server.Script("12345678903", accrualtest.TooManyRequests(30, 120))
+
+	client := NewHTTPClient(ClientConfig{BaseURL: server.URL})
+	_, err := client.GetOrder(context.Background(), "12345678903")
+
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) {
+		t.Fatalf("Expected RateLimitError, got %v", err)
+	}
+	assert.ErrorIs(t, err, customerror.ErrTooManyRequests)
+	assert.Equal(t, 30*time.Second, rateLimitErr.RetryAfter)
+	assert.Equal(t, 120, rateLimitErr.RequestsPerMinute)
+}
+
+func TestGetOrder_InternalError(t *testing.T) {
+	server := accrualtest.NewServer()
+	defer server.Close()
+	server.Script("12345678903", accrualtest.InternalError())
+
+	client := NewHTTPClient(ClientConfig{BaseURL: server.URL})
+	_, err := client.GetOrder(context.Background(), "12345678903")
+
+	assert.ErrorIs(t, err, customerror.ErrAccrualUnavailable)
+}
+
+func TestAccrualOutcome(t *testing.T) {
+	assert.Equal(t, "200", accrualOutcome(http.StatusOK))
+	assert.Equal(t, "204", accrualOutcome(http.StatusNoContent))
+	assert.Equal(t, "429", accrualOutcome(http.StatusTooManyRequests))
+	assert.Equal(t, "5xx", accrualOutcome(http.StatusBadGateway))
+	assert.Equal(t, "other", accrualOutcome(http.StatusNotFound))
+}
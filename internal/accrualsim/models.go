@@ -0,0 +1,40 @@
+package accrualsim
+
+import "github.com/with0p/gophermart/internal/models"
+
+type Good struct {
+	Description string        `json:"description"`
+	Price       models.Amount `json:"price"`
+}
+
+type RewardType string
+
+const (
+	RewardPercent RewardType = "%"
+	RewardPoints  RewardType = "pt"
+)
+
+// Rule rewards every good whose description contains Match.
+type Rule struct {
+	Match      string        `json:"match"`
+	Reward     models.Amount `json:"reward"`
+	RewardType RewardType    `json:"reward_type"`
+}
+
+type OrderRegistration struct {
+	Order string `json:"order"`
+	Goods []Good `json:"goods"`
+}
+
+type OrderResponse struct {
+	Order   string         `json:"order"`
+	Status  string         `json:"status"`
+	Accrual *models.Amount `json:"accrual,omitempty"`
+}
+
+const (
+	StatusRegistered = "REGISTERED"
+	StatusProcessing = "PROCESSING"
+	StatusInvalid    = "INVALID"
+	StatusProcessed  = "PROCESSED"
+)
@@ -0,0 +1,71 @@
+package accrualsim
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+)
+
+// Handler serves the accrual system API: order lookups as gophermart makes
+// them, plus the order and reward rule registration used to set them up.
+func (s *Simulator) Handler() http.Handler {
+	mux := chi.NewRouter()
+	mux.Get("/api/orders/{number}", s.handleLookup)
+	mux.Post("/api/orders", s.handleRegisterOrder)
+	mux.Post("/api/goods", s.handleRegisterRule)
+	return mux
+}
+
+func (s *Simulator) handleLookup(w http.ResponseWriter, r *http.Request) {
+	response, retryAfter, err := s.Lookup(chi.URLParam(r, "number"))
+	switch {
+	case errors.Is(err, customerror.ErrTooManyRequests):
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("No more than " + strconv.Itoa(s.conf.RequestsPerMinute) + " requests per minute allowed"))
+	case errors.Is(err, customerror.ErrOrderNotRegistered):
+		w.WriteHeader(http.StatusNoContent)
+	case err != nil:
+		w.WriteHeader(http.StatusInternalServerError)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+func (s *Simulator) handleRegisterOrder(w http.ResponseWriter, r *http.Request) {
+	var registration OrderRegistration
+	if err := json.NewDecoder(r.Body).Decode(&registration); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(registrationStatus(s.RegisterOrder(registration), http.StatusAccepted))
+}
+
+func (s *Simulator) handleRegisterRule(w http.ResponseWriter, r *http.Request) {
+	var rule Rule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(registrationStatus(s.RegisterRule(rule), http.StatusOK))
+}
+
+func registrationStatus(err error, success int) int {
+	switch {
+	case err == nil:
+		return success
+	case errors.Is(err, customerror.ErrWrongOrderFormat):
+		return http.StatusBadRequest
+	case errors.Is(err, customerror.ErrOrderAlreadyRegistered), errors.Is(err, customerror.ErrRewardRuleExists):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,211 @@
+// Package accrualsim simulates the accrual system for local development:
+// orders and reward rules are registered over HTTP and kept in memory, and
+// orders move through REGISTERED and PROCESSING to PROCESSED or INVALID on
+// their own.
+package accrualsim
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/theplant/luhn"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+type Config struct {
+	// RequestsPerMinute limits order lookups; 0 disables throttling.
+	RequestsPerMinute int
+	// An order stays REGISTERED and then PROCESSING for a random time
+	// between MinDelay and MaxDelay each.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// InvalidRate is the share of orders that end up INVALID.
+	InvalidRate float64
+	// AutoRegister makes lookups of unknown valid numbers register them with
+	// a random accrual of up to AutoAccrualMax, so that orders uploaded to
+	// gophermart get processed without registering them first.
+	AutoRegister   bool
+	AutoAccrualMax models.Amount
+	Seed           int64
+}
+
+type order struct {
+	number       string
+	accrual      models.Amount
+	invalid      bool
+	processingAt time.Time
+	doneAt       time.Time
+}
+
+// Simulator is an in-memory stand-in for the accrual system.
+type Simulator struct {
+	conf Config
+	now  func() time.Time
+
+	mu       sync.Mutex
+	random   *rand.Rand
+	orders   map[string]*order
+	rules    []Rule
+	throttle throttle
+}
+
+func NewSimulator(conf Config) *Simulator {
+	if conf.MaxDelay < conf.MinDelay {
+		conf.MaxDelay = conf.MinDelay
+	}
+	if conf.AutoAccrualMax < 0 {
+		conf.AutoAccrualMax = 0
+	}
+	seed := conf.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Simulator{
+		conf:     conf,
+		now:      time.Now,
+		random:   rand.New(rand.NewSource(seed)),
+		orders:   make(map[string]*order),
+		throttle: throttle{limit: conf.RequestsPerMinute},
+	}
+}
+
+func validNumber(number string) bool {
+	n, err := strconv.ParseInt(number, 10, 64)
+	return err == nil && luhn.Valid(int(n))
+}
+
+func (s *Simulator) RegisterRule(rule Rule) error {
+	if rule.Match == "" || rule.Reward <= 0 || (rule.RewardType != RewardPercent && rule.RewardType != RewardPoints) {
+		return customerror.ErrWrongOrderFormat
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.rules {
+		if existing.Match == rule.Match {
+			return customerror.ErrRewardRuleExists
+		}
+	}
+	s.rules = append(s.rules, rule)
+	return nil
+}
+
+func (s *Simulator) RegisterOrder(registration OrderRegistration) error {
+	if !validNumber(registration.Order) || len(registration.Goods) == 0 {
+		return customerror.ErrWrongOrderFormat
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.orders[registration.Order]; ok {
+		return customerror.ErrOrderAlreadyRegistered
+	}
+	s.addOrder(registration.Order, s.reward(registration.Goods))
+	return nil
+}
+
+// Lookup reports the order as the accrual system would. It returns
+// ErrTooManyRequests along with the time to wait when throttled, and
+// ErrOrderNotRegistered for unknown orders.
+func (s *Simulator) Lookup(number string) (*OrderResponse, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if retryAfter, ok := s.throttle.allow(now); !ok {
+		return nil, retryAfter, customerror.ErrTooManyRequests
+	}
+
+	o, ok := s.orders[number]
+	if !ok {
+		if !s.conf.AutoRegister || !validNumber(number) {
+			return nil, 0, customerror.ErrOrderNotRegistered
+		}
+		o = s.addOrder(number, models.Amount(s.random.Int63n(int64(s.conf.AutoAccrualMax)+1)))
+	}
+
+	response := &OrderResponse{Order: o.number}
+	switch {
+	case now.Before(o.processingAt):
+		response.Status = StatusRegistered
+	case now.Before(o.doneAt):
+		response.Status = StatusProcessing
+	case o.invalid:
+		response.Status = StatusInvalid
+	default:
+		response.Status = StatusProcessed
+		accrual := o.accrual
+		response.Accrual = &accrual
+	}
+	return response, 0, nil
+}
+
+func (s *Simulator) addOrder(number string, accrual models.Amount) *order {
+	now := s.now()
+	processingAt := now.Add(s.delay())
+	o := &order{
+		number:       number,
+		accrual:      accrual,
+		invalid:      s.random.Float64() < s.conf.InvalidRate,
+		processingAt: processingAt,
+		doneAt:       processingAt.Add(s.delay()),
+	}
+	s.orders[number] = o
+	return o
+}
+
+func (s *Simulator) delay() time.Duration {
+	spread := s.conf.MaxDelay - s.conf.MinDelay
+	if spread <= 0 {
+		return s.conf.MinDelay
+	}
+	return s.conf.MinDelay + time.Duration(s.random.Int63n(int64(spread)+1))
+}
+
+// reward applies the first matching rule to every good.
+func (s *Simulator) reward(goods []Good) models.Amount {
+	var total models.Amount
+	for _, good := range goods {
+		for _, rule := range s.rules {
+			if !strings.Contains(good.Description, rule.Match) {
+				continue
+			}
+			if rule.RewardType == RewardPoints {
+				total += rule.Reward
+			} else {
+				// both are in hundredths, so the percentage is scaled twice
+				total += models.Amount((int64(good.Price)*int64(rule.Reward) + 5000) / 10000)
+			}
+			break
+		}
+	}
+	return total
+}
+
+// throttle admits limit lookups per minute-long window.
+type throttle struct {
+	limit       int
+	windowStart time.Time
+	count       int
+}
+
+func (t *throttle) allow(now time.Time) (time.Duration, bool) {
+	if t.limit <= 0 {
+		return 0, true
+	}
+	if now.Sub(t.windowStart) >= time.Minute {
+		t.windowStart = now
+		t.count = 0
+	}
+	if t.count >= t.limit {
+		return t.windowStart.Add(time.Minute).Sub(now), false
+	}
+	t.count++
+	return 0, true
+}
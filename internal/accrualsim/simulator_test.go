@@ -0,0 +1,100 @@
+package accrualsim
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func newTestSimulator(conf Config) (*Simulator, *clock) {
+	c := &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	s := NewSimulator(conf)
+	s.now = c.Now
+	return s, c
+}
+
+func do(t *testing.T, s *Simulator, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	s.Handler().ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return w
+}
+
+func TestOrderLifecycle(t *testing.T) {
+	s, c := newTestSimulator(Config{MinDelay: time.Second, MaxDelay: time.Second, Seed: 1})
+
+	require.Equal(t, http.StatusOK, do(t, s, http.MethodPost, "/api/goods", `{"match":"Bork","reward":10,"reward_type":"%"}`).Code)
+	require.Equal(t, http.StatusOK, do(t, s, http.MethodPost, "/api/goods", `{"match":"LG","reward":15,"reward_type":"pt"}`).Code)
+	require.Equal(t, http.StatusAccepted, do(t, s, http.MethodPost, "/api/orders",
+		`{"order":"79927398713","goods":[{"description":"Чайник Bork","price":7000},{"description":"Телевизор LG","price":50000},{"description":"Шнур","price":100}]}`).Code)
+
+	lookup := func() OrderResponse {
+		w := do(t, s, http.MethodGet, "/api/orders/79927398713", "")
+		require.Equal(t, http.StatusOK, w.Code)
+		var response OrderResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		return response
+	}
+
+	assert.Equal(t, OrderResponse{Order: "79927398713", Status: StatusRegistered}, lookup())
+	c.now = c.now.Add(time.Second)
+	assert.Equal(t, OrderResponse{Order: "79927398713", Status: StatusProcessing}, lookup())
+	c.now = c.now.Add(time.Second)
+	accrual := models.Amount(71500)
+	assert.Equal(t, OrderResponse{Order: "79927398713", Status: StatusProcessed, Accrual: &accrual}, lookup())
+}
+
+func TestRegistrationErrors(t *testing.T) {
+	s, _ := newTestSimulator(Config{})
+
+	order := `{"order":"79927398713","goods":[{"description":"Шнур","price":100}]}`
+	assert.Equal(t, http.StatusAccepted, do(t, s, http.MethodPost, "/api/orders", order).Code)
+	assert.Equal(t, http.StatusConflict, do(t, s, http.MethodPost, "/api/orders", order).Code)
+	assert.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/api/orders", `{"order":"79927398710","goods":[{"description":"Шнур","price":100}]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/api/orders", `{"order":"4561261212345467"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/api/orders", `{`).Code)
+
+	rule := `{"match":"Bork","reward":10,"reward_type":"%"}`
+	assert.Equal(t, http.StatusOK, do(t, s, http.MethodPost, "/api/goods", rule).Code)
+	assert.Equal(t, http.StatusConflict, do(t, s, http.MethodPost, "/api/goods", rule).Code)
+	assert.Equal(t, http.StatusBadRequest, do(t, s, http.MethodPost, "/api/goods", `{"match":"LG","reward":10,"reward_type":"x"}`).Code)
+}
+
+func TestUnknownOrders(t *testing.T) {
+	s, _ := newTestSimulator(Config{})
+	assert.Equal(t, http.StatusNoContent, do(t, s, http.MethodGet, "/api/orders/79927398713", "").Code)
+
+	s, _ = newTestSimulator(Config{AutoRegister: true, AutoAccrualMax: 50000, InvalidRate: 1})
+	assert.Equal(t, http.StatusNoContent, do(t, s, http.MethodGet, "/api/orders/79927398710", "").Code)
+
+	w := do(t, s, http.MethodGet, "/api/orders/79927398713", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"order":"79927398713","status":"INVALID"}`, w.Body.String())
+}
+
+func TestThrottle(t *testing.T) {
+	s, c := newTestSimulator(Config{RequestsPerMinute: 2})
+
+	assert.Equal(t, http.StatusNoContent, do(t, s, http.MethodGet, "/api/orders/79927398713", "").Code)
+	c.now = c.now.Add(20 * time.Second)
+	assert.Equal(t, http.StatusNoContent, do(t, s, http.MethodGet, "/api/orders/79927398713", "").Code)
+
+	w := do(t, s, http.MethodGet, "/api/orders/79927398713", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "40", w.Header().Get("Retry-After"))
+	assert.Equal(t, "No more than 2 requests per minute allowed", w.Body.String())
+
+	c.now = c.now.Add(40 * time.Second)
+	assert.Equal(t, http.StatusNoContent, do(t, s, http.MethodGet, "/api/orders/79927398713", "").Code)
+}
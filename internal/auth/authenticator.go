@@ -0,0 +1,38 @@
+package auth
+
+import "time"
+
+type CredentialSource string
+
+const (
+	CredentialHeader CredentialSource = "header"
+	CredentialCookie CredentialSource = "cookie"
+)
+
+type Options struct {
+	// Precedence picks the credential used when a request carries both an
+	// Authorization header and an auth cookie.
+	Precedence      CredentialSource
+	CSRFProtection  bool
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+type Authenticator struct {
+	keys     *KeySet
+	sessions SessionStore
+	options  Options
+}
+
+func NewAuthenticator(keys *KeySet, sessions SessionStore, options Options) *Authenticator {
+	if options.Precedence == "" {
+		options.Precedence = CredentialHeader
+	}
+	if options.AccessTokenTTL <= 0 {
+		options.AccessTokenTTL = defaultAccessTokenTTL
+	}
+	if options.RefreshTokenTTL <= 0 {
+		options.RefreshTokenTTL = defaultRefreshTokenTTL
+	}
+	return &Authenticator{keys: keys, sessions: sessions, options: options}
+}
@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+)
+
+const (
+	csrfCookieName = "csrf_token"
+	csrfHeaderName = "X-CSRF-Token"
+)
+
+// credentialFromRequest returns the access token and whether it was taken
+// from the auth cookie.
+func (a *Authenticator) credentialFromRequest(r *http.Request) (string, bool) {
+	header := bearerToken(r)
+	cookie := ""
+	if c, err := r.Cookie(authCookieName); err == nil {
+		cookie = c.Value
+	}
+
+	useCookie := header == "" || (a.options.Precedence == CredentialCookie && cookie != "")
+	if useCookie {
+		return cookie, cookie != ""
+	}
+	return header, false
+}
+
+func bearerToken(r *http.Request) string {
+	value := r.Header.Get("Authorization")
+	scheme, token, ok := strings.Cut(value, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// validCSRF implements the double-submit check: a state-changing request
+// authenticated by cookie must echo the csrf_token cookie in X-CSRF-Token.
+func validCSRF(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	cookie, err := r.Cookie(csrfCookieName)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+	header := r.Header.Get(csrfHeaderName)
+
+	return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) == 1
+}
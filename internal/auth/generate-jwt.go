@@ -4,25 +4,27 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 type Claims struct {
-	Login string `json:"login"`
+	Login     string    `json:"login"`
+	SessionID uuid.UUID `json:"sid"`
+	Roles     []string  `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
-const secretKey = "extremely_secret_key_gophermart"
-
-func GenerateJWT(login string, expitationTime time.Time) (string, error) {
+func (a *Authenticator) GenerateJWT(login string, sessionID uuid.UUID, roles []string, expitationTime time.Time) (string, error) {
 	claims := Claims{
-		Login: login,
+		Login:     login,
+		SessionID: sessionID,
+		Roles:     roles,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expitationTime),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(secretKey))
+	tokenString, err := a.keys.Sign(claims)
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"context"
+)
+
+func GetRolesFromRequestContext(ctx context.Context) []string {
+	roles, _ := ctx.Value(RolesKey).([]string)
+	return roles
+}
+
+func HasRole(ctx context.Context, role string) bool {
+	for _, r := range GetRolesFromRequestContext(ctx) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+func GetSessionIDFromRequestContext(ctx context.Context) (uuid.UUID, error) {
+	sessionID, ok := ctx.Value(SessionKey).(uuid.UUID)
+	if !ok {
+		return uuid.Nil, errors.New("no session found")
+	}
+
+	return sessionID, nil
+}
@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+type SigningKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	// SignKey is nil for keys that are only kept to verify tokens issued
+	// before a rotation.
+	SignKey   interface{}
+	VerifyKey interface{}
+}
+
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+func NewKeySet(activeID string, keys ...SigningKey) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*SigningKey, len(keys))}
+	for i := range keys {
+		key := keys[i]
+		if key.ID == "" {
+			return nil, errors.New("jwt key without kid")
+		}
+		if _, ok := ks.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate jwt kid %q", key.ID)
+		}
+		ks.keys[key.ID] = &key
+	}
+
+	active, ok := ks.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("active jwt kid %q is not in the key set", activeID)
+	}
+	if active.SignKey == nil {
+		return nil, fmt.Errorf("active jwt kid %q has no private key", activeID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+// NewRandomKeySet returns a single HS256 key that only lives as long as the
+// process; tokens do not survive a restart.
+func NewRandomKeySet() (*KeySet, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return NewKeySet("ephemeral", SigningKey{
+		ID:        "ephemeral",
+		Method:    jwt.SigningMethodHS256,
+		SignKey:   secret,
+		VerifyKey: secret,
+	})
+}
+
+func NewSecretKeySet(secret string) (*KeySet, error) {
+	return NewKeySet("default", SigningKey{
+		ID:        "default",
+		Method:    jwt.SigningMethodHS256,
+		SignKey:   []byte(secret),
+		VerifyKey: []byte(secret),
+	})
+}
+
+type keySetFile struct {
+	ActiveKID string        `json:"active_kid"`
+	Keys      []keyFileItem `json:"keys"`
+}
+
+type keyFileItem struct {
+	KID            string `json:"kid"`
+	Alg            string `json:"alg"`
+	Secret         string `json:"secret"`
+	PrivateKeyFile string `json:"private_key_file"`
+	PublicKeyFile  string `json:"public_key_file"`
+}
+
+// LoadKeySet reads a JSON key set file. HS256 keys carry a secret, RS256 and
+// EdDSA keys reference PEM files relative to the key set file; keys without a
+// private key are accepted for verification only.
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keySetFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse jwt key set %s: %w", path, err)
+	}
+
+	dir := filepath.Dir(path)
+	keys := make([]SigningKey, 0, len(file.Keys))
+	for _, item := range file.Keys {
+		key, err := item.signingKey(dir)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %q: %w", item.KID, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(file.ActiveKID, keys...)
+}
+
+func (item keyFileItem) signingKey(dir string) (SigningKey, error) {
+	key := SigningKey{ID: item.KID}
+
+	readPEM := func(name string) ([]byte, error) {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(dir, name)
+		}
+		return os.ReadFile(name)
+	}
+
+	switch item.Alg {
+	case jwt.SigningMethodHS256.Alg():
+		if item.Secret == "" {
+			return key, errors.New("HS256 key requires a secret")
+		}
+		key.Method = jwt.SigningMethodHS256
+		key.SignKey = []byte(item.Secret)
+		key.VerifyKey = []byte(item.Secret)
+
+	case jwt.SigningMethodRS256.Alg():
+		key.Method = jwt.SigningMethodRS256
+		if item.PrivateKeyFile != "" {
+			data, err := readPEM(item.PrivateKeyFile)
+			if err != nil {
+				return key, err
+			}
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+			if err != nil {
+				return key, err
+			}
+			key.SignKey = private
+			key.VerifyKey = &private.PublicKey
+		}
+		if item.PublicKeyFile != "" {
+			data, err := readPEM(item.PublicKeyFile)
+			if err != nil {
+				return key, err
+			}
+			public, err := jwt.ParseRSAPublicKeyFromPEM(data)
+			if err != nil {
+				return key, err
+			}
+			key.VerifyKey = public
+		}
+
+	case jwt.SigningMethodEdDSA.Alg():
+		key.Method = jwt.SigningMethodEdDSA
+		if item.PrivateKeyFile != "" {
+			data, err := readPEM(item.PrivateKeyFile)
+			if err != nil {
+				return key, err
+			}
+			private, err := jwt.ParseEdPrivateKeyFromPEM(data)
+			if err != nil {
+				return key, err
+			}
+			edPrivate, ok := private.(ed25519.PrivateKey)
+			if !ok {
+				return key, errors.New("private key is not an Ed25519 key")
+			}
+			key.SignKey = edPrivate
+			key.VerifyKey = edPrivate.Public()
+		}
+		if item.PublicKeyFile != "" {
+			data, err := readPEM(item.PublicKeyFile)
+			if err != nil {
+				return key, err
+			}
+			public, err := jwt.ParseEdPublicKeyFromPEM(data)
+			if err != nil {
+				return key, err
+			}
+			key.VerifyKey = public
+		}
+
+	default:
+		return key, fmt.Errorf("unsupported alg %q", item.Alg)
+	}
+
+	if key.VerifyKey == nil {
+		return key, errors.New("key requires a private or public key file")
+	}
+
+	return key, nil
+}
+
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.active.Method, claims)
+	token.Header["kid"] = ks.active.ID
+
+	return token.SignedString(ks.active.SignKey)
+}
+
+// Parse verifies the token against the key named by its kid header and only
+// accepts the algorithm configured for that key.
+func (ks *KeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	var key *SigningKey
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		var ok bool
+		key, ok = ks.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("unknown jwt kid %q", kid)
+		}
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected jwt alg %q for kid %q", t.Method.Alg(), kid)
+		}
+		return key.VerifyKey, nil
+	}, jwt.WithValidMethods([]string{
+		jwt.SigningMethodHS256.Alg(),
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodEdDSA.Alg(),
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
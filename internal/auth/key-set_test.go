@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testClaims() Claims {
+	return Claims{
+		Login: "user1",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+}
+
+func TestKeySet_SignAndParse(t *testing.T) {
+	ks, err := NewSecretKeySet("secret")
+	require.NoError(t, err)
+
+	tokenString, err := ks.Sign(testClaims())
+	require.NoError(t, err)
+
+	claims := &Claims{}
+	token, err := ks.Parse(tokenString, claims)
+	require.NoError(t, err)
+	assert.True(t, token.Valid)
+	assert.Equal(t, "default", token.Header["kid"])
+	assert.Equal(t, "user1", claims.Login)
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	oldKey := SigningKey{ID: "old", Method: jwt.SigningMethodHS256, SignKey: []byte("old"), VerifyKey: []byte("old")}
+	newKey := SigningKey{ID: "new", Method: jwt.SigningMethodEdDSA, SignKey: edPrivate, VerifyKey: edPrivate.Public()}
+
+	before, err := NewKeySet("old", oldKey)
+	require.NoError(t, err)
+	oldToken, err := before.Sign(testClaims())
+	require.NoError(t, err)
+
+	oldKey.SignKey = nil
+	after, err := NewKeySet("new", newKey, oldKey)
+	require.NoError(t, err)
+
+	_, err = after.Parse(oldToken, &Claims{})
+	assert.NoError(t, err)
+
+	newToken, err := after.Sign(testClaims())
+	require.NoError(t, err)
+	token, err := after.Parse(newToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "new", token.Header["kid"])
+
+	_, err = before.Parse(newToken, &Claims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_RejectsAlgorithmMismatch(t *testing.T) {
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ks, err := NewKeySet("rsa", SigningKey{ID: "rsa", Method: jwt.SigningMethodRS256, SignKey: rsaPrivate, VerifyKey: &rsaPrivate.PublicKey})
+	require.NoError(t, err)
+
+	publicDER, err := x509.MarshalPKIXPublicKey(&rsaPrivate.PublicKey)
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	forged.Header["kid"] = "rsa"
+	forgedString, err := forged.SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}))
+	require.NoError(t, err)
+
+	_, err = ks.Parse(forgedString, &Claims{})
+	assert.Error(t, err)
+}
+
+func TestKeySet_RejectsUnknownKID(t *testing.T) {
+	other, err := NewKeySet("other", SigningKey{ID: "other", Method: jwt.SigningMethodHS256, SignKey: []byte("secret"), VerifyKey: []byte("secret")})
+	require.NoError(t, err)
+	ks, err := NewSecretKeySet("secret")
+	require.NoError(t, err)
+
+	tokenString, err := other.Sign(testClaims())
+	require.NoError(t, err)
+
+	_, err = ks.Parse(tokenString, &Claims{})
+	assert.Error(t, err)
+}
+
+func TestLoadKeySet(t *testing.T) {
+	dir := t.TempDir()
+
+	_, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privateDER, err := x509.MarshalPKCS8PrivateKey(edPrivate)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ed25519.pem"), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600))
+
+	keySetJSON := `{
+		"active_kid": "ed",
+		"keys": [
+			{"kid": "ed", "alg": "EdDSA", "private_key_file": "ed25519.pem"},
+			{"kid": "legacy", "alg": "HS256", "secret": "legacy_secret"}
+		]
+	}`
+	path := filepath.Join(dir, "keys.json")
+	require.NoError(t, os.WriteFile(path, []byte(keySetJSON), 0o600))
+
+	ks, err := LoadKeySet(path)
+	require.NoError(t, err)
+
+	tokenString, err := ks.Sign(testClaims())
+	require.NoError(t, err)
+	token, err := ks.Parse(tokenString, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, jwt.SigningMethodEdDSA.Alg(), token.Method.Alg())
+}
@@ -0,0 +1,17 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SessionStore interface {
+	CreateSession(ctx context.Context, login string, refreshTokenHash string, expiresAt time.Time) (uuid.UUID, error)
+	RotateRefreshToken(ctx context.Context, oldHash string, newHash string, expiresAt time.Time) (uuid.UUID, string, error)
+	IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error)
+	RevokeSession(ctx context.Context, sessionID uuid.UUID) error
+	RevokeUserSessions(ctx context.Context, login string) error
+	GetUserRoles(ctx context.Context, login string) ([]string, error)
+}
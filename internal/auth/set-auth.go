@@ -1,28 +1,166 @@
 package auth
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"net/http"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+	authCookieName    = "auth_token"
+	refreshCookieName = "refresh_token"
+	refreshCookiePath = "/api/user"
 )
 
-const tokenExp = time.Hour * 24
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+// SetAuth opens a new session for login and sends its tokens as cookies and
+// in the Authorization header.
+func (a *Authenticator) SetAuth(ctx context.Context, w http.ResponseWriter, login string) (*TokenPair, error) {
+	refreshToken, refreshHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, err := a.sessions.CreateSession(ctx, login, refreshHash, time.Now().Add(a.options.RefreshTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return a.issueTokens(ctx, w, login, sessionID, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Presenting a
+// refresh token that was already exchanged revokes the whole session.
+func (a *Authenticator) Refresh(ctx context.Context, w http.ResponseWriter, refreshToken string) (*TokenPair, error) {
+	newRefreshToken, newRefreshHash, err := newRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, login, err := a.sessions.RotateRefreshToken(ctx, hashRefreshToken(refreshToken), newRefreshHash, time.Now().Add(a.options.RefreshTokenTTL))
+	if err != nil {
+		return nil, err
+	}
+
+	return a.issueTokens(ctx, w, login, sessionID, newRefreshToken)
+}
+
+func (a *Authenticator) Logout(ctx context.Context, w http.ResponseWriter, sessionID uuid.UUID) error {
+	if err := a.sessions.RevokeSession(ctx, sessionID); err != nil {
+		return err
+	}
+
+	clearAuthCookies(w)
+	return nil
+}
+
+func (a *Authenticator) LogoutAll(ctx context.Context, w http.ResponseWriter, login string) error {
+	if err := a.sessions.RevokeUserSessions(ctx, login); err != nil {
+		return err
+	}
+
+	clearAuthCookies(w)
+	return nil
+}
+
+func (a *Authenticator) issueTokens(ctx context.Context, w http.ResponseWriter, login string, sessionID uuid.UUID, refreshToken string) (*TokenPair, error) {
+	roles, err := a.sessions.GetUserRoles(ctx, login)
+	if err != nil {
+		return nil, err
+	}
 
-func SetAuth(r *http.Request, w http.ResponseWriter, login string) {
-	expTime := time.Now().Add(tokenExp)
+	expTime := time.Now().Add(a.options.AccessTokenTTL)
 
-	tokenString, err := GenerateJWT(login, expTime)
+	tokenString, err := a.GenerateJWT(login, sessionID, roles, expTime)
 	if err != nil {
-		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
-		return
+		return nil, err
 	}
 
 	http.SetCookie(w, &http.Cookie{
-		Name:     "auth_token",
+		Name:     authCookieName,
 		Value:    tokenString,
 		Expires:  expTime,
 		Path:     "/",
 		HttpOnly: true,
 	})
 
+	http.SetCookie(w, &http.Cookie{
+		Name:     refreshCookieName,
+		Value:    refreshToken,
+		Expires:  time.Now().Add(a.options.RefreshTokenTTL),
+		Path:     refreshCookiePath,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	csrfToken, err := newRandomToken()
+	if err != nil {
+		return nil, err
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    csrfToken,
+		Expires:  time.Now().Add(a.options.RefreshTokenTTL),
+		Path:     "/",
+		SameSite: http.SameSiteLaxMode,
+	})
+
 	w.Header().Set("Authorization", "Bearer "+tokenString)
+
+	return &TokenPair{
+		AccessToken:  tokenString,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(a.options.AccessTokenTTL.Seconds()),
+	}, nil
+}
+
+func clearAuthCookies(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{Name: authCookieName, Path: "/", MaxAge: -1, HttpOnly: true})
+	http.SetCookie(w, &http.Cookie{Name: refreshCookieName, Path: refreshCookiePath, MaxAge: -1, HttpOnly: true})
+	http.SetCookie(w, &http.Cookie{Name: csrfCookieName, Path: "/", MaxAge: -1})
+}
+
+func newRandomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func newRefreshToken() (string, string, error) {
+	token, err := newRandomToken()
+	if err != nil {
+		return "", "", err
+	}
+
+	return token, hashRefreshToken(token), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func RefreshTokenFromRequest(r *http.Request) string {
+	cookie, err := r.Cookie(refreshCookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
 }
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"net/http"
+)
+
+// UseRequireRole must be wrapped by UseValidateAuth, which puts the token
+// roles into the request context.
+func (a *Authenticator) UseRequireRole(role string, next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !HasRole(r.Context(), role) {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
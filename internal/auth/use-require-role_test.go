@@ -0,0 +1,55 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseRequireRole_Allowed(t *testing.T) {
+	ctrl, mockSessions, a := setupAuthenticator(t)
+	defer ctrl.Finish()
+
+	sessionID := uuid.New()
+	tokenString, err := a.GenerateJWT("admin1", sessionID, []string{"admin"}, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	mockSessions.EXPECT().IsSessionActive(gomock.Any(), sessionID).Return(true, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	rr := httptest.NewRecorder()
+
+	called := false
+	a.UseValidateAuth(a.UseRequireRole("admin", func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, called)
+}
+
+func TestUseRequireRole_Forbidden(t *testing.T) {
+	ctrl, mockSessions, a := setupAuthenticator(t)
+	defer ctrl.Finish()
+
+	sessionID := uuid.New()
+	tokenString, err := a.GenerateJWT("user1", sessionID, nil, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	mockSessions.EXPECT().IsSessionActive(gomock.Any(), sessionID).Return(true, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/users", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	rr := httptest.NewRecorder()
+
+	a.UseValidateAuth(a.UseRequireRole("admin", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler must not be called without the role")
+	}))(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
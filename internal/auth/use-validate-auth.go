@@ -4,32 +4,52 @@ import (
 	"context"
 	"net/http"
 
-	"github.com/golang-jwt/jwt/v4"
+	"github.com/with0p/gophermart/internal/logger"
 )
 
 type ctxLoginKey string
 
 var LoginKey ctxLoginKey = "login"
+var SessionKey ctxLoginKey = "session"
+var RolesKey ctxLoginKey = "roles"
 
-func UseValidateAuth(next http.HandlerFunc) http.HandlerFunc {
+func (a *Authenticator) UseValidateAuth(next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		cookie, err := r.Cookie("auth_token")
-		if err != nil {
+		tokenString, fromCookie := a.credentialFromRequest(r)
+		if tokenString == "" {
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
 			return
 		}
 
+		if fromCookie && a.options.CSRFProtection && !validCSRF(r) {
+			http.Error(w, "invalid csrf token", http.StatusForbidden)
+			return
+		}
+
 		claims := &Claims{}
-		token, err := jwt.ParseWithClaims(cookie.Value, claims, func(t *jwt.Token) (interface{}, error) {
-			return []byte(secretKey), nil
-		})
+		token, err := a.keys.Parse(tokenString, claims)
 
 		var updatedRequest *http.Request
 		if err != nil || !token.Valid {
 			http.Error(w, "unauthorized", http.StatusUnauthorized)
 			return
 		}
+
+		active, err := a.sessions.IsSessionActive(r.Context(), claims.SessionID)
+		if err != nil {
+			logger.ErrorContext(r.Context(), err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !active {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
 		ctx := context.WithValue(r.Context(), LoginKey, claims.Login)
+		ctx = context.WithValue(ctx, SessionKey, claims.SessionID)
+		ctx = context.WithValue(ctx, RolesKey, claims.Roles)
+		ctx = logger.With(ctx, "login", claims.Login)
 		updatedRequest = r.WithContext(ctx)
 
 		next.ServeHTTP(w, updatedRequest)
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/with0p/gophermart/internal/mock"
+)
+
+func setupAuthenticator(t *testing.T) (*gomock.Controller, *mock.MockSessionStore, *Authenticator) {
+	ctrl := gomock.NewController(t)
+	mockSessions := mock.NewMockSessionStore(ctrl)
+	keys, err := NewSecretKeySet("test_secret")
+	require.NoError(t, err)
+	return ctrl, mockSessions, NewAuthenticator(keys, mockSessions, Options{CSRFProtection: true})
+}
+
+func TestUseValidateAuth_ActiveSession(t *testing.T) {
+	ctrl, mockSessions, a := setupAuthenticator(t)
+	defer ctrl.Finish()
+
+	sessionID := uuid.New()
+	tokenString, err := a.GenerateJWT("user1", sessionID, nil, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	mockSessions.EXPECT().IsSessionActive(gomock.Any(), sessionID).Return(true, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+	req.AddCookie(&http.Cookie{Name: "auth_token", Value: tokenString})
+	rr := httptest.NewRecorder()
+
+	var login string
+	a.UseValidateAuth(func(w http.ResponseWriter, r *http.Request) {
+		login, _ = GetLoginFromRequestContext(r.Context())
+	})(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "user1", login)
+}
+
+func TestUseValidateAuth_RevokedSession(t *testing.T) {
+	ctrl, mockSessions, a := setupAuthenticator(t)
+	defer ctrl.Finish()
+
+	sessionID := uuid.New()
+	tokenString, err := a.GenerateJWT("user1", sessionID, nil, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	mockSessions.EXPECT().IsSessionActive(gomock.Any(), sessionID).Return(false, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+	req.AddCookie(&http.Cookie{Name: "auth_token", Value: tokenString})
+	rr := httptest.NewRecorder()
+
+	a.UseValidateAuth(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler must not be called for a revoked session")
+	})(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestUseValidateAuth_ExpiredToken(t *testing.T) {
+	ctrl, _, a := setupAuthenticator(t)
+	defer ctrl.Finish()
+
+	tokenString, err := a.GenerateJWT("user1", uuid.New(), nil, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+	req.AddCookie(&http.Cookie{Name: "auth_token", Value: tokenString})
+	rr := httptest.NewRecorder()
+
+	a.UseValidateAuth(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler must not be called for an expired token")
+	})(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestUseValidateAuth_BearerHeader(t *testing.T) {
+	ctrl, mockSessions, a := setupAuthenticator(t)
+	defer ctrl.Finish()
+
+	sessionID := uuid.New()
+	tokenString, err := a.GenerateJWT("user1", sessionID, nil, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	mockSessions.EXPECT().IsSessionActive(gomock.Any(), sessionID).Return(true, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/orders", nil)
+	req.Header.Set("Authorization", "Bearer "+tokenString)
+	rr := httptest.NewRecorder()
+
+	a.UseValidateAuth(func(w http.ResponseWriter, r *http.Request) {})(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestUseValidateAuth_CookieWithoutCSRF(t *testing.T) {
+	ctrl, _, a := setupAuthenticator(t)
+	defer ctrl.Finish()
+
+	tokenString, err := a.GenerateJWT("user1", uuid.New(), nil, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/orders", nil)
+	req.AddCookie(&http.Cookie{Name: "auth_token", Value: tokenString})
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "csrf"})
+	rr := httptest.NewRecorder()
+
+	a.UseValidateAuth(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler must not be called without a CSRF token")
+	})(rr, req)
+
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
+
+func TestUseValidateAuth_CookieWithCSRF(t *testing.T) {
+	ctrl, mockSessions, a := setupAuthenticator(t)
+	defer ctrl.Finish()
+
+	sessionID := uuid.New()
+	tokenString, err := a.GenerateJWT("user1", sessionID, nil, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	mockSessions.EXPECT().IsSessionActive(gomock.Any(), sessionID).Return(true, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/orders", nil)
+	req.AddCookie(&http.Cookie{Name: "auth_token", Value: tokenString})
+	req.AddCookie(&http.Cookie{Name: "csrf_token", Value: "csrf"})
+	req.Header.Set("X-CSRF-Token", "csrf")
+	rr := httptest.NewRecorder()
+
+	a.UseValidateAuth(func(w http.ResponseWriter, r *http.Request) {})(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestUseValidateAuth_CookiePrecedence(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockSessions := mock.NewMockSessionStore(ctrl)
+	keys, err := NewSecretKeySet("test_secret")
+	require.NoError(t, err)
+	a := NewAuthenticator(keys, mockSessions, Options{Precedence: CredentialCookie})
+
+	sessionID := uuid.New()
+	cookieToken, err := a.GenerateJWT("cookie_user", sessionID, nil, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	headerToken, err := a.GenerateJWT("header_user", uuid.New(), nil, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	mockSessions.EXPECT().IsSessionActive(gomock.Any(), sessionID).Return(true, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+	req.AddCookie(&http.Cookie{Name: "auth_token", Value: cookieToken})
+	req.Header.Set("Authorization", "Bearer "+headerToken)
+	rr := httptest.NewRecorder()
+
+	var login string
+	a.UseValidateAuth(func(w http.ResponseWriter, r *http.Request) {
+		login, _ = GetLoginFromRequestContext(r.Context())
+	})(rr, req)
+
+	assert.Equal(t, "cookie_user", login)
+}
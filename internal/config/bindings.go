@@ -0,0 +1,127 @@
+package config
+
+import (
+	"flag"
+	"strconv"
+	"time"
+)
+
+// binding ties a field to its environment variable and flag. Fields without
+// a flag, such as secrets, can only come from the file or the environment.
+type binding struct {
+	key   string
+	env   string
+	flag  string
+	usage string
+	value flag.Value
+}
+
+func (c *Config) bindings() []binding {
+	return []binding{
+		{"server.address", "RUN_ADDRESS", "a", "address to listen on", stringValue{&c.Server.Address}},
+		{"server.read_header_timeout", "SERVER_READ_HEADER_TIMEOUT", "read-header-timeout", "time to read request headers", durationValue{&c.Server.ReadHeaderTimeout}},
+		{"server.read_timeout", "SERVER_READ_TIMEOUT", "read-timeout", "time to read a whole request", durationValue{&c.Server.ReadTimeout}},
+		{"server.idle_timeout", "SERVER_IDLE_TIMEOUT", "idle-timeout", "keep-alive timeout", durationValue{&c.Server.IdleTimeout}},
+		{"server.shutdown_delay", "SHUTDOWN_DELAY", "shutdown-delay", "how long /readyz fails before the server stops", durationValue{&c.Server.ShutdownDelay}},
+		{"server.shutdown_timeout", "SHUTDOWN_TIMEOUT", "shutdown-timeout", "time given to in-flight requests on shutdown", durationValue{&c.Server.ShutdownTimeout}},
+
+		{"database.uri", "DATABASE_URI", "d", "PostgreSQL connection string", stringValue{&c.Database.URI}},
+		{"database.max_open_conns", "DB_MAX_OPEN_CONNS", "db-max-open-conns", "connection pool size, 0 for unlimited", intValue{&c.Database.MaxOpenConns}},
+		{"database.max_idle_conns", "DB_MAX_IDLE_CONNS", "db-max-idle-conns", "idle connections kept in the pool", intValue{&c.Database.MaxIdleConns}},
+		{"database.conn_max_lifetime", "DB_CONN_MAX_LIFETIME", "db-conn-max-lifetime", "maximum connection age, 0 for unlimited", durationValue{&c.Database.ConnMaxLifetime}},
+
+		{"accrual.address", "ACCRUAL_SYSTEM_ADDRESS", "r", "accrual system address", stringValue{&c.Accrual.Address}},
+		{"accrual.timeout", "ACCRUAL_TIMEOUT", "accrual-timeout", "accrual request timeout", durationValue{&c.Accrual.Timeout}},
+		{"accrual.workers", "ACCRUAL_WORKERS", "accrual-workers", "orders checked against the accrual system at once", intValue{&c.Accrual.Workers}},
+		{"accrual.queue_size", "ACCRUAL_QUEUE_SIZE", "accrual-queue-size", "job queue depth reported as backed up", intValue{&c.Accrual.QueueSize}},
+		{"accrual.database_uri", "ACCRUAL_DATABASE_URI", "accrual-database-uri", "database of the accrual binary run in dev mode", stringValue{&c.Accrual.DatabaseURI}},
+		{"accrual.max_attempts", "ACCRUAL_MAX_ATTEMPTS", "accrual-max-attempts", "checks before a failing order is given up on", intValue{&c.Accrual.MaxAttempts}},
+
+		{"auth.jwt_keys_file", "JWT_KEYS_FILE", "jwt-keys", "JWT signing keys file", stringValue{&c.Auth.JWTKeysFile}},
+		{"auth.jwt_secret", "JWT_SECRET", "", "JWT signing secret", stringValue{&c.Auth.JWTSecret}},
+		{"auth.precedence", "AUTH_PRECEDENCE", "auth-precedence", "header or cookie", stringValue{&c.Auth.Precedence}},
+		{"auth.csrf_protection", "CSRF_PROTECTION", "csrf", "require a CSRF token with cookie auth", boolValue{&c.Auth.CSRFProtection}},
+		{"auth.access_token_ttl", "ACCESS_TOKEN_TTL", "access-token-ttl", "access token lifetime", durationValue{&c.Auth.AccessTokenTTL}},
+		{"auth.refresh_token_ttl", "REFRESH_TOKEN_TTL", "refresh-token-ttl", "refresh token lifetime", durationValue{&c.Auth.RefreshTokenTTL}},
+
+		{"log.level", "LOG_LEVEL", "log-level", "debug, info, warn or error", stringValue{&c.Log.Level}},
+		{"log.format", "LOG_FORMAT", "log-format", "json or console", stringValue{&c.Log.Format}},
+
+		{"tracing.exporter", "TRACE_EXPORTER", "trace-exporter", "stdout or otlp, empty to disable", stringValue{&c.Tracing.Exporter}},
+
+		{"features.metrics", "FEATURE_METRICS", "metrics", "serve /metrics", boolValue{&c.Features.Metrics}},
+		{"features.webhook_delivery", "FEATURE_WEBHOOK_DELIVERY", "webhook-delivery", "deliver queued webhooks", boolValue{&c.Features.WebhookDelivery}},
+	}
+}
+
+type stringValue struct{ p *string }
+
+func (v stringValue) String() string {
+	if v.p == nil {
+		return ""
+	}
+	return *v.p
+}
+
+func (v stringValue) Set(s string) error {
+	*v.p = s
+	return nil
+}
+
+type intValue struct{ p *int }
+
+func (v intValue) String() string {
+	if v.p == nil {
+		return "0"
+	}
+	return strconv.Itoa(*v.p)
+}
+
+func (v intValue) Set(s string) error {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return err
+	}
+	*v.p = n
+	return nil
+}
+
+type boolValue struct{ p *bool }
+
+func (v boolValue) String() string {
+	if v.p == nil {
+		return "false"
+	}
+	return strconv.FormatBool(*v.p)
+}
+
+func (v boolValue) Set(s string) error {
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	*v.p = b
+	return nil
+}
+
+func (v boolValue) IsBoolFlag() bool {
+	return true
+}
+
+type durationValue struct{ p *time.Duration }
+
+func (v durationValue) String() string {
+	if v.p == nil {
+		return "0s"
+	}
+	return v.p.String()
+}
+
+func (v durationValue) Set(s string) error {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*v.p = d
+	return nil
+}
@@ -1,54 +1,209 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"flag"
+	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"gopkg.in/yaml.v3"
 )
 
-const defaultBaseURL = "localhost:8080"
+// Config is loaded from, in increasing order of precedence: the defaults, a
+// YAML or TOML file, the environment and the command line flags.
+type Config struct {
+	Server   ServerConfig   `yaml:"server" toml:"server"`
+	Database DatabaseConfig `yaml:"database" toml:"database"`
+	Accrual  AccrualConfig  `yaml:"accrual" toml:"accrual"`
+	Auth     AuthConfig     `yaml:"auth" toml:"auth"`
+	Log      LogConfig      `yaml:"log" toml:"log"`
+	Tracing  TracingConfig  `yaml:"tracing" toml:"tracing"`
+	Features FeaturesConfig `yaml:"features" toml:"features"`
+}
 
-// const defaultDataBaseAddress = "host=localhost port=5435 user=postgres password=1234 dbname=postgres sslmode=disable"
-// const defaultAccrualURL = "localhost:8081"
+type ServerConfig struct {
+	Address           string        `yaml:"address" toml:"address"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout" toml:"read_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout"`
+	// ShutdownDelay is how long /readyz fails before the server stops
+	// accepting connections.
+	ShutdownDelay   time.Duration `yaml:"shutdown_delay" toml:"shutdown_delay"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
 
-const defaultAccrualURL = ""
-const defaultDataBaseAddress = ""
+type DatabaseConfig struct {
+	URI             string        `yaml:"uri" toml:"uri"`
+	MaxOpenConns    int           `yaml:"max_open_conns" toml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns" toml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime" toml:"conn_max_lifetime"`
+}
 
-type Config struct {
-	BaseURL         string
-	AccrualURL      string
-	DataBaseAddress string
+type AccrualConfig struct {
+	Address string        `yaml:"address" toml:"address"`
+	Timeout time.Duration `yaml:"timeout" toml:"timeout"`
+	Workers int           `yaml:"workers" toml:"workers"`
+	// QueueSize is the job queue depth at which readiness reports the queue
+	// as backed up.
+	QueueSize int `yaml:"queue_size" toml:"queue_size"`
+	// DatabaseURI is handed to the accrual binary run by `gophermart dev`,
+	// which must not share gophermart's schema.
+	DatabaseURI string `yaml:"database_uri" toml:"database_uri"`
+	// MaxAttempts is how many checks an order gets before failures stop
+	// being retried.
+	MaxAttempts int `yaml:"max_attempts" toml:"max_attempts"`
 }
 
-var configuration *Config
+type AuthConfig struct {
+	JWTKeysFile     string        `yaml:"jwt_keys_file" toml:"jwt_keys_file"`
+	JWTSecret       string        `yaml:"jwt_secret" toml:"jwt_secret"`
+	Precedence      string        `yaml:"precedence" toml:"precedence"`
+	CSRFProtection  bool          `yaml:"csrf_protection" toml:"csrf_protection"`
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl" toml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" toml:"refresh_token_ttl"`
+}
 
-func GetConfig() *Config {
-	if configuration == nil {
-		var conf = Config{}
+type LogConfig struct {
+	Level  string `yaml:"level" toml:"level"`
+	Format string `yaml:"format" toml:"format"`
+}
 
-		flag.StringVar(&conf.BaseURL, "a", defaultBaseURL, "RUN_ADDRESS")
-		flag.StringVar(&conf.DataBaseAddress, "d", defaultDataBaseAddress, "DATABASE_URI")
-		flag.StringVar(&conf.AccrualURL, "r", defaultAccrualURL, "ACCRUAL_SYSTEM_ADDRESS")
-		flag.Parse()
+type TracingConfig struct {
+	// Exporter is empty, stdout or otlp.
+	Exporter string `yaml:"exporter" toml:"exporter"`
+}
 
-		if envServerAddress := os.Getenv("RUN_ADDRESS"); envServerAddress != "" {
-			conf.BaseURL = envServerAddress
-		}
+type FeaturesConfig struct {
+	Metrics         bool `yaml:"metrics" toml:"metrics"`
+	WebhookDelivery bool `yaml:"webhook_delivery" toml:"webhook_delivery"`
+}
+
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Address:           "localhost:8080",
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			ShutdownDelay:     5 * time.Second,
+			ShutdownTimeout:   10 * time.Second,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Accrual: AccrualConfig{
+			Timeout:     10 * time.Second,
+			Workers:     3,
+			QueueSize:   1000,
+			MaxAttempts: 50,
+		},
+		Auth: AuthConfig{
+			Precedence:      "header",
+			CSRFProtection:  true,
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 30 * 24 * time.Hour,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Features: FeaturesConfig{
+			Metrics:         true,
+			WebhookDelivery: true,
+		},
+	}
+}
 
-		if envDatabaseStoragePath := os.Getenv("DATABASE_URI"); envDatabaseStoragePath != "" {
-			conf.DataBaseAddress = envDatabaseStoragePath
+// Load builds the configuration from args (without the program name) and the
+// environment. It returns the arguments left after the flags.
+func Load(args []string) (*Config, []string, error) {
+	parsed := Default()
+	flags := flag.NewFlagSet("gophermart", flag.ContinueOnError)
+	configFile := flags.String("config", "", "YAML or TOML config file; CONFIG_FILE")
+	for _, b := range parsed.bindings() {
+		if b.flag != "" {
+			flags.Var(b.value, b.flag, fmt.Sprintf("%s; %s", b.usage, b.env))
 		}
+	}
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, err
+	}
+
+	conf := Default()
 
-		if envAccrualAddress := os.Getenv("ACCRUAL_SYSTEM_ADDRESS"); envAccrualAddress != "" {
-			conf.AccrualURL = envAccrualAddress
+	path := *configFile
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := conf.loadFile(path); err != nil {
+			return nil, nil, err
 		}
+	}
+
+	if err := conf.loadEnv(); err != nil {
+		return nil, nil, err
+	}
 
-		configuration = &Config{
-			BaseURL:         conf.BaseURL,
-			DataBaseAddress: conf.DataBaseAddress,
-			AccrualURL:      conf.AccrualURL,
+	// flags were parsed into a scratch copy so that only the ones actually
+	// given override the file and the environment
+	bindings := make(map[string]binding)
+	for _, b := range conf.bindings() {
+		bindings[b.flag] = b
+	}
+	flags.Visit(func(f *flag.Flag) {
+		if b, ok := bindings[f.Name]; ok {
+			b.value.Set(f.Value.String())
 		}
+	})
+
+	return &conf, flags.Args(), nil
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
 	}
 
-	return configuration
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), c)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("%s: unknown key %q", path, undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("%w: %s", customerror.ErrUnknownConfigFormat, path)
+	}
+	return nil
+}
 
+func (c *Config) loadEnv() error {
+	for _, b := range c.bindings() {
+		value, ok := os.LookupEnv(b.env)
+		if !ok || value == "" {
+			continue
+		}
+		if err := b.value.Set(value); err != nil {
+			return fmt.Errorf("%s: %w", b.env, err)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Validate reports every problem at once, naming the key, the environment
+// variable and the flag of each offending setting.
+func (c *Config) Validate() error {
+	var problems []error
+	problem := func(key string, format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%s: %s", c.describe(key), fmt.Sprintf(format, args...)))
+	}
+
+	if _, _, err := net.SplitHostPort(c.Server.Address); err != nil {
+		problem("server.address", "must be host:port, got %q", c.Server.Address)
+	}
+	for _, setting := range []struct {
+		key   string
+		value int64
+	}{
+		{"server.read_header_timeout", int64(c.Server.ReadHeaderTimeout)},
+		{"server.read_timeout", int64(c.Server.ReadTimeout)},
+		{"server.idle_timeout", int64(c.Server.IdleTimeout)},
+		{"server.shutdown_delay", int64(c.Server.ShutdownDelay)},
+		{"database.max_open_conns", int64(c.Database.MaxOpenConns)},
+		{"database.max_idle_conns", int64(c.Database.MaxIdleConns)},
+		{"database.conn_max_lifetime", int64(c.Database.ConnMaxLifetime)},
+	} {
+		if setting.value < 0 {
+			problem(setting.key, "must not be negative")
+		}
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		problem("server.shutdown_timeout", "must be positive")
+	}
+
+	if c.Database.URI == "" {
+		problem("database.uri", "is required")
+	}
+
+	if c.Accrual.Timeout <= 0 {
+		problem("accrual.timeout", "must be positive")
+	}
+	if c.Accrual.Workers < 1 {
+		problem("accrual.workers", "must be at least 1")
+	}
+	if c.Accrual.QueueSize < 1 {
+		problem("accrual.queue_size", "must be at least 1")
+	}
+	if c.Accrual.MaxAttempts < 1 {
+		problem("accrual.max_attempts", "must be at least 1")
+	}
+
+	if c.Auth.Precedence != "header" && c.Auth.Precedence != "cookie" {
+		problem("auth.precedence", "must be header or cookie, got %q", c.Auth.Precedence)
+	}
+	if c.Auth.AccessTokenTTL <= 0 {
+		problem("auth.access_token_ttl", "must be positive")
+	}
+	if c.Auth.RefreshTokenTTL <= c.Auth.AccessTokenTTL {
+		problem("auth.refresh_token_ttl", "must be longer than auth.access_token_ttl")
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		problem("log.level", "must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	switch c.Log.Format {
+	case "json", "console":
+	default:
+		problem("log.format", "must be json or console, got %q", c.Log.Format)
+	}
+
+	switch c.Tracing.Exporter {
+	case "", "stdout", "otlp":
+	default:
+		problem("tracing.exporter", "must be stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+
+	return errors.Join(problems...)
+}
+
+// ValidateServer is Validate plus what is only needed to serve requests. A
+// signing key is required: an ephemeral one would log everybody out on every
+// restart and differ between replicas.
+func (c *Config) ValidateServer() error {
+	err := c.Validate()
+	if c.Accrual.Address == "" {
+		err = errors.Join(err, fmt.Errorf("%s: is required", c.describe("accrual.address")))
+	}
+	if c.Auth.JWTKeysFile == "" && c.Auth.JWTSecret == "" {
+		err = errors.Join(err, fmt.Errorf("%s or %s: one is required", c.describe("auth.jwt_keys_file"), c.describe("auth.jwt_secret")))
+	}
+	return err
+}
+
+func (c *Config) describe(key string) string {
+	for _, b := range c.bindings() {
+		if b.key != key {
+			continue
+		}
+		if b.flag == "" {
+			return fmt.Sprintf("%s (%s)", key, b.env)
+		}
+		return fmt.Sprintf("%s (%s, -%s)", key, b.env, b.flag)
+	}
+	return key
+}
+
+const redacted = "REDACTED"
+
+var passwordParam = regexp.MustCompile(`(password=)('[^']*'|\S+)`)
+
+// Redacted returns a copy that is safe to print.
+func (c Config) Redacted() Config {
+	if c.Auth.JWTSecret != "" {
+		c.Auth.JWTSecret = redacted
+	}
+	c.Database.URI = redactURI(c.Database.URI)
+	c.Accrual.DatabaseURI = redactURI(c.Accrual.DatabaseURI)
+	return c
+}
+
+func redactURI(uri string) string {
+	if strings.Contains(uri, "://") {
+		parsed, err := url.Parse(uri)
+		if err != nil {
+			return redacted
+		}
+		if _, ok := parsed.User.Password(); ok {
+			parsed.User = url.UserPassword(parsed.User.Username(), redacted)
+		}
+		query := parsed.Query()
+		if query.Has("password") {
+			query.Set("password", redacted)
+			parsed.RawQuery = query.Encode()
+		}
+		return parsed.String()
+	}
+	return passwordParam.ReplaceAllString(uri, "${1}"+redacted)
+}
@@ -9,3 +9,34 @@ var ErrAlreadyAdded = errors.New("order already added by this user")
 var ErrWrongOrderFormat = errors.New("wrong order format")
 var ErrInsufficientBalance = errors.New("insufficient balance")
 var ErrTooManyRequests = errors.New("to many requests")
+var ErrOrderNotRegistered = errors.New("order is not registered in accrual system")
+var ErrAccrualUnavailable = errors.New("accrual system unavailable")
+var ErrSchemaMismatch = errors.New("database schema version mismatch")
+var ErrWithdrawalAlreadyExists = errors.New("withdrawal for this order already exists")
+var ErrIdempotencyKeyMismatch = errors.New("idempotency key reused with a different request")
+var ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is in progress")
+var ErrInvalidRefreshToken = errors.New("invalid refresh token")
+var ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+var ErrUserLocked = errors.New("user is locked")
+var ErrNoSuchOrder = errors.New("no such order")
+var ErrReasonRequired = errors.New("reason is required")
+var ErrUnknownRole = errors.New("unknown role")
+var ErrBatchTooLarge = errors.New("batch is too large")
+var ErrInvalidCursor = errors.New("invalid cursor")
+var ErrUnknownTimeZone = errors.New("unknown time zone")
+var ErrNoSuchWebhook = errors.New("no such webhook")
+var ErrNoSuchWebhookDelivery = errors.New("no such webhook delivery")
+var ErrInvalidWebhookURL = errors.New("webhook url must be an absolute https url")
+var ErrForbiddenWebhookTarget = errors.New("webhook url must not point to a private, loopback or link-local address")
+var ErrUnknownWebhookEvent = errors.New("unknown webhook event type")
+var ErrUnknownTraceExporter = errors.New("unknown trace exporter")
+var ErrUnknownLogLevel = errors.New("unknown log level")
+var ErrUnknownLogFormat = errors.New("unknown log format")
+var ErrUnknownConfigFormat = errors.New("config file must be .yaml, .yml or .toml")
+var ErrOrderAlreadyRegistered = errors.New("order is already registered")
+var ErrRewardRuleExists = errors.New("reward rule for this match already exists")
+var ErrWorkersStarted = errors.New("workers are already started")
+var ErrWorkersNotStarted = errors.New("workers are not started")
+var ErrWorkersStopTimeout = errors.New("workers did not finish in time")
+var ErrWebhookDeliveryPending = errors.New("webhook delivery is still pending")
+var ErrInvalidWithdrawalSum = errors.New("withdrawal sum must be positive")
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+const subscriberBuffer = 64
+
+// Broker fans order events out to the streams of their user within one
+// instance. A subscriber that falls behind has its channel closed instead of
+// silently losing events, so it can resume from the last event it got.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[chan models.OrderEvent]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[uuid.UUID]map[chan models.OrderEvent]struct{})}
+}
+
+func (b *Broker) Subscribe(userID uuid.UUID) (<-chan models.OrderEvent, func()) {
+	ch := make(chan models.OrderEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan models.OrderEvent]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(userID, ch)
+	}
+}
+
+func (b *Broker) Publish(event models.OrderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[event.UserID] {
+		select {
+		case ch <- event:
+		default:
+			b.remove(event.UserID, ch)
+		}
+	}
+}
+
+func (b *Broker) remove(userID uuid.UUID, ch chan models.OrderEvent) {
+	if _, ok := b.subscribers[userID][ch]; !ok {
+		return
+	}
+
+	delete(b.subscribers[userID], ch)
+	if len(b.subscribers[userID]) == 0 {
+		delete(b.subscribers, userID)
+	}
+	close(ch)
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestBroker_PublishToUser(t *testing.T) {
+	b := NewBroker()
+	userID := uuid.New()
+
+	events, unsubscribe := b.Subscribe(userID)
+	defer unsubscribe()
+	other, unsubscribeOther := b.Subscribe(uuid.New())
+	defer unsubscribeOther()
+
+	b.Publish(models.OrderEvent{ID: 1, UserID: userID, OrderID: "12345678903"})
+
+	assert.Equal(t, int64(1), (<-events).ID)
+	assert.Empty(t, other)
+}
+
+func TestBroker_SlowSubscriberIsClosed(t *testing.T) {
+	b := NewBroker()
+	userID := uuid.New()
+
+	events, unsubscribe := b.Subscribe(userID)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Publish(models.OrderEvent{ID: int64(i + 1), UserID: userID})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+
+	unsubscribe()
+}
@@ -44,7 +44,6 @@ func (h *HandlerUserAPI) AddOrder(w http.ResponseWriter, r *http.Request) {
 	switch {
 	case errOrder == nil:
 		statusCode = http.StatusAccepted
-		h.queue <- orderID
 	case errors.Is(errOrder, customerror.ErrAnotherUserOrder):
 		statusCode = http.StatusConflict
 	case errors.Is(errOrder, customerror.ErrWrongOrderFormat):
@@ -53,7 +52,7 @@ func (h *HandlerUserAPI) AddOrder(w http.ResponseWriter, r *http.Request) {
 		statusCode = http.StatusOK
 	default:
 		statusCode = http.StatusInternalServerError
-		logger.Error(errOrder)
+		logger.ErrorContext(ctx, errOrder, "order_id", orderID)
 	}
 
 	w.WriteHeader(statusCode)
@@ -14,7 +14,7 @@ import (
 )
 
 func TestAddOrder_MethodNotPost(t *testing.T) {
-	ctrl, _, handler := setupWithQueue(t)
+	ctrl, _, handler := setup(t)
 	defer ctrl.Finish()
 
 	req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
@@ -28,7 +28,7 @@ func TestAddOrder_MethodNotPost(t *testing.T) {
 }
 
 func TestAddOrder_WrongContentType(t *testing.T) {
-	ctrl, _, handler := setupWithQueue(t)
+	ctrl, _, handler := setup(t)
 	defer ctrl.Finish()
 
 	req := httptest.NewRequest(http.MethodPost, "/api/user/orders", nil)
@@ -43,7 +43,7 @@ func TestAddOrder_WrongContentType(t *testing.T) {
 }
 
 func TestAddOrder_AuthError(t *testing.T) {
-	ctrl, _, handler := setupWithQueue(t)
+	ctrl, _, handler := setup(t)
 	defer ctrl.Finish()
 
 	req := httptest.NewRequest(http.MethodPost, "/api/user/orders", strings.NewReader("12345678903"))
@@ -60,7 +60,7 @@ func TestAddOrder_AuthError(t *testing.T) {
 }
 
 func TestAddOrder_ErrAnotherUserOrder(t *testing.T) {
-	ctrl, mockService, handler := setupWithQueue(t)
+	ctrl, mockService, handler := setup(t)
 	defer ctrl.Finish()
 
 	mockService.EXPECT().AddOrder(gomock.Any(), "login", models.OrderID("1230")).Return(customerror.ErrAnotherUserOrder)
@@ -79,7 +79,7 @@ func TestAddOrder_ErrAnotherUserOrder(t *testing.T) {
 }
 
 func TestAddOrder_ErrWrongOrderFormat(t *testing.T) {
-	ctrl, mockService, handler := setupWithQueue(t)
+	ctrl, mockService, handler := setup(t)
 	defer ctrl.Finish()
 
 	mockService.EXPECT().AddOrder(gomock.Any(), "login", models.OrderID("12345678903")).Return(customerror.ErrWrongOrderFormat)
@@ -98,7 +98,7 @@ func TestAddOrder_ErrWrongOrderFormat(t *testing.T) {
 }
 
 func TestAddOrder_ErrAlreadyAdded(t *testing.T) {
-	ctrl, mockService, handler := setupWithQueue(t)
+	ctrl, mockService, handler := setup(t)
 	defer ctrl.Finish()
 
 	mockService.EXPECT().AddOrder(gomock.Any(), "login", models.OrderID("12345678903")).Return(customerror.ErrAlreadyAdded)
@@ -117,7 +117,7 @@ func TestAddOrder_ErrAlreadyAdded(t *testing.T) {
 }
 
 func TestAddOrder_Success(t *testing.T) {
-	ctrl, mockService, handler := setupWithQueue(t)
+	ctrl, mockService, handler := setup(t)
 	defer ctrl.Finish()
 
 	mockService.EXPECT().AddOrder(gomock.Any(), "login", models.OrderID("1230")).Return(nil)
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/with0p/gophermart/internal/auth"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/logger"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+const (
+	OrderResultAccepted     = "accepted"
+	OrderResultAlreadyAdded = "already_added"
+	OrderResultAnotherUser  = "another_user_order"
+	OrderResultWrongFormat  = "wrong_format"
+)
+
+const maxOrderBatchBodySize = 1 << 20
+
+type OrderBatchResult struct {
+	OrderID models.OrderID `json:"number"`
+	Result  string         `json:"result"`
+}
+
+func (h *HandlerUserAPI) AddOrdersBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Not a POST requests", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	login, errLogin := auth.GetLoginFromRequestContext(ctx)
+	if errLogin != nil {
+		http.Error(w, errLogin.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	body, errRead := io.ReadAll(http.MaxBytesReader(w, r.Body, maxOrderBatchBodySize))
+	defer r.Body.Close()
+	if errRead != nil {
+		var errTooLarge *http.MaxBytesError
+		if errors.As(errRead, &errTooLarge) {
+			http.Error(w, errRead.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, errRead.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var orderIDs []models.OrderID
+	var errParse error
+	switch r.Header.Get("content-type") {
+	case "application/json":
+		orderIDs, errParse = parseOrderBatchJSON(body)
+	case "text/plain":
+		orderIDs = parseOrderBatchText(body)
+	default:
+		http.Error(w, "Not a \"application/json\" or \"text/plain\" content-type", http.StatusBadRequest)
+		return
+	}
+	if errParse != nil {
+		http.Error(w, errParse.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(orderIDs) == 0 {
+		http.Error(w, "no order numbers", http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.service.AddOrders(ctx, login, orderIDs)
+	if err != nil {
+		if errors.Is(err, customerror.ErrBatchTooLarge) {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		logger.ErrorContext(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := make([]OrderBatchResult, len(results))
+	for i, result := range results {
+		response[i] = OrderBatchResult{OrderID: result.OrderID, Result: orderBatchResult(result.Err)}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+// parseOrderBatchJSON accepts an array of order numbers written either as
+// strings or as JSON numbers.
+func parseOrderBatchJSON(body []byte) ([]models.OrderID, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var values []any
+	if err := decoder.Decode(&values); err != nil {
+		return nil, err
+	}
+
+	orderIDs := make([]models.OrderID, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case string:
+			orderIDs[i] = models.OrderID(v)
+		case json.Number:
+			orderIDs[i] = models.OrderID(v.String())
+		default:
+			return nil, errors.New("order numbers must be strings or numbers")
+		}
+	}
+
+	return orderIDs, nil
+}
+
+func parseOrderBatchText(body []byte) []models.OrderID {
+	var orderIDs []models.OrderID
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			orderIDs = append(orderIDs, models.OrderID(line))
+		}
+	}
+	return orderIDs
+}
+
+func orderBatchResult(err error) string {
+	switch {
+	case err == nil:
+		return OrderResultAccepted
+	case errors.Is(err, customerror.ErrAlreadyAdded):
+		return OrderResultAlreadyAdded
+	case errors.Is(err, customerror.ErrAnotherUserOrder):
+		return OrderResultAnotherUser
+	default:
+		return OrderResultWrongFormat
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/with0p/gophermart/internal/auth"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func newBatchRequest(body string, contentType string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/user/orders/batch", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	return req.WithContext(context.WithValue(req.Context(), auth.LoginKey, "user1"))
+}
+
+func TestAddOrdersBatch_JSON(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().AddOrders(gomock.Any(), "user1", []models.OrderID{"12345678903", "4561261212345467", "123"}).
+		Return([]models.OrderUploadResult{
+			{OrderID: "12345678903"},
+			{OrderID: "4561261212345467", Err: customerror.ErrAlreadyAdded},
+			{OrderID: "123", Err: customerror.ErrWrongOrderFormat},
+		}, nil)
+
+	rr := httptest.NewRecorder()
+	handler.AddOrdersBatch(rr, newBatchRequest(`["12345678903", 4561261212345467, "123"]`, "application/json"))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	var results []OrderBatchResult
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &results))
+	assert.Equal(t, []OrderBatchResult{
+		{OrderID: "12345678903", Result: OrderResultAccepted},
+		{OrderID: "4561261212345467", Result: OrderResultAlreadyAdded},
+		{OrderID: "123", Result: OrderResultWrongFormat},
+	}, results)
+}
+
+func TestAddOrdersBatch_Text(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().AddOrders(gomock.Any(), "user1", []models.OrderID{"12345678903", "4561261212345467"}).
+		Return([]models.OrderUploadResult{
+			{OrderID: "12345678903"},
+			{OrderID: "4561261212345467", Err: customerror.ErrAnotherUserOrder},
+		}, nil)
+
+	rr := httptest.NewRecorder()
+	handler.AddOrdersBatch(rr, newBatchRequest("12345678903\r\n\n 4561261212345467 \n", "text/plain"))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), OrderResultAnotherUser)
+}
+
+func TestAddOrdersBatch_InvalidJSON(t *testing.T) {
+	ctrl, _, handler := setup(t)
+	defer ctrl.Finish()
+
+	rr := httptest.NewRecorder()
+	handler.AddOrdersBatch(rr, newBatchRequest(`[{"number":"12345678903"}]`, "application/json"))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestAddOrdersBatch_Empty(t *testing.T) {
+	ctrl, _, handler := setup(t)
+	defer ctrl.Finish()
+
+	rr := httptest.NewRecorder()
+	handler.AddOrdersBatch(rr, newBatchRequest("\n\n", "text/plain"))
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestAddOrdersBatch_TooLarge(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().AddOrders(gomock.Any(), "user1", gomock.Any()).Return(nil, customerror.ErrBatchTooLarge)
+
+	rr := httptest.NewRecorder()
+	handler.AddOrdersBatch(rr, newBatchRequest("12345678903", "text/plain"))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+}
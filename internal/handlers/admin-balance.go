@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/with0p/gophermart/internal/auth"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+type BalanceAdjustmentData struct {
+	Amount models.Amount `json:"amount"`
+	Reason string        `json:"reason"`
+}
+
+func (h *HandlerAdminAPI) AdjustBalance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	actor, errLogin := auth.GetLoginFromRequestContext(ctx)
+	if errLogin != nil {
+		http.Error(w, errLogin.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var data BalanceAdjustmentData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if data.Amount == 0 {
+		http.Error(w, "amount must not be zero", http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.AdjustBalance(ctx, actor, chi.URLParam(r, "login"), data.Amount, data.Reason)
+	writeAdminError(w, err)
+}
+
+// RecheckOrder asks the accrual system about the order again. Final orders
+// are checked too; a changed accrual is corrected in the ledger.
+func (h *HandlerAdminAPI) RecheckOrder(w http.ResponseWriter, r *http.Request) {
+	err := h.service.RecheckOrder(r.Context(), models.OrderID(chi.URLParam(r, "number")))
+	if err == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	writeAdminError(w, err)
+}
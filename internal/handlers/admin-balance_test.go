@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestAdminAdjustBalance_Success(t *testing.T) {
+	ctrl, mockService, h := setupAdmin(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().AdjustBalance(gomock.Any(), "admin1", "bob", models.Amount(-1050), "refund").Return(nil)
+
+	rr := adminRequest(t, h, http.MethodPost, "/users/bob/adjustments", `{"amount":-10.5,"reason":"refund"}`, []string{models.RoleAdmin})
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status code %v, got %v", http.StatusOK, status)
+	}
+}
+
+func TestAdminAdjustBalance_ReasonRequired(t *testing.T) {
+	ctrl, mockService, h := setupAdmin(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().AdjustBalance(gomock.Any(), "admin1", "bob", models.Amount(1000), "").Return(customerror.ErrReasonRequired)
+
+	rr := adminRequest(t, h, http.MethodPost, "/users/bob/adjustments", `{"amount":10}`, []string{models.RoleAdmin})
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("Expected status code %v, got %v", http.StatusBadRequest, status)
+	}
+}
+
+func TestAdminAdjustBalance_InsufficientBalance(t *testing.T) {
+	ctrl, mockService, h := setupAdmin(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().AdjustBalance(gomock.Any(), "admin1", "bob", models.Amount(-1000), "chargeback").Return(customerror.ErrInsufficientBalance)
+
+	rr := adminRequest(t, h, http.MethodPost, "/users/bob/adjustments", `{"amount":-10,"reason":"chargeback"}`, []string{models.RoleAdmin})
+
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("Expected status code %v, got %v", http.StatusConflict, status)
+	}
+}
+
+func TestAdminRecheckOrder_Success(t *testing.T) {
+	ctrl, mockService, h := setupAdmin(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().RecheckOrder(gomock.Any(), models.OrderID("12345678903")).Return(nil)
+
+	rr := adminRequest(t, h, http.MethodPost, "/orders/12345678903/recheck", "", []string{models.RoleAdmin})
+
+	if status := rr.Code; status != http.StatusAccepted {
+		t.Errorf("Expected status code %v, got %v", http.StatusAccepted, status)
+	}
+}
+
+func TestAdminRecheckOrder_NoSuchOrder(t *testing.T) {
+	ctrl, mockService, h := setupAdmin(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().RecheckOrder(gomock.Any(), models.OrderID("12345678903")).Return(customerror.ErrNoSuchOrder)
+
+	rr := adminRequest(t, h, http.MethodPost, "/orders/12345678903/recheck", "", []string{models.RoleAdmin})
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("Expected status code %v, got %v", http.StatusNotFound, status)
+	}
+}
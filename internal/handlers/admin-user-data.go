@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+)
+
+func (h *HandlerAdminAPI) GetUserOrders(w http.ResponseWriter, r *http.Request) {
+	q, errQuery := parseListQuery(r, orderSortFields, true)
+	if errQuery != nil {
+		http.Error(w, errQuery.Error(), http.StatusBadRequest)
+		return
+	}
+
+	orders, next, err := h.service.GetUserOrders(r.Context(), chi.URLParam(r, "login"), q)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	setNextLink(w, r, next)
+	writeJSON(w, http.StatusOK, orders)
+}
+
+func (h *HandlerAdminAPI) GetUserWithdrawals(w http.ResponseWriter, r *http.Request) {
+	q, errQuery := parseListQuery(r, withdrawalSortFields, false)
+	if errQuery != nil {
+		http.Error(w, errQuery.Error(), http.StatusBadRequest)
+		return
+	}
+
+	withdrawals, next, err := h.service.GetUserWithdrawals(r.Context(), chi.URLParam(r, "login"), q)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	setNextLink(w, r, next)
+	writeJSON(w, http.StatusOK, withdrawals)
+}
+
+func (h *HandlerAdminAPI) GetUserBalance(w http.ResponseWriter, r *http.Request) {
+	balance, err := h.service.GetUserBalance(r.Context(), chi.URLParam(r, "login"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, balance)
+}
+
+func (h *HandlerAdminAPI) GetUserLedger(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.service.GetUserLedger(r.Context(), chi.URLParam(r, "login"))
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
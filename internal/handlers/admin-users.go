@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+)
+
+const (
+	adminDefaultPageSize = 50
+	adminMaxPageSize     = 500
+)
+
+type LockUserData struct {
+	Reason string `json:"reason"`
+}
+
+type UserRolesData struct {
+	Roles []string `json:"roles"`
+}
+
+func (h *HandlerAdminAPI) ListUsers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := adminDefaultPageSize
+	if value := query.Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > adminMaxPageSize {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	offset := 0
+	if value := query.Get("offset"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			http.Error(w, "invalid offset", http.StatusBadRequest)
+			return
+		}
+		offset = parsed
+	}
+
+	users, err := h.service.ListUsers(r.Context(), query.Get("q"), limit, offset)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (h *HandlerAdminAPI) LockUser(w http.ResponseWriter, r *http.Request) {
+	var data LockUserData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.LockUser(r.Context(), chi.URLParam(r, "login"), data.Reason)
+	writeAdminError(w, err)
+}
+
+func (h *HandlerAdminAPI) UnlockUser(w http.ResponseWriter, r *http.Request) {
+	err := h.service.UnlockUser(r.Context(), chi.URLParam(r, "login"))
+	writeAdminError(w, err)
+}
+
+func (h *HandlerAdminAPI) SetUserRoles(w http.ResponseWriter, r *http.Request) {
+	var data UserRolesData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.SetUserRoles(r.Context(), chi.URLParam(r, "login"), data.Roles)
+	writeAdminError(w, err)
+}
+
+// writeAdminError answers 200 on success and maps the service errors shared
+// by the admin endpoints.
+func writeAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case err == nil:
+		w.WriteHeader(http.StatusOK)
+	case errors.Is(err, customerror.ErrNoSuchUser), errors.Is(err, customerror.ErrNoSuchOrder):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, customerror.ErrReasonRequired), errors.Is(err, customerror.ErrUnknownRole):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, customerror.ErrInsufficientBalance):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (h *HandlerAdminAPI) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	serveCreateWebhook(w, r, h.service, chi.URLParam(r, "login"))
+}
+
+func (h *HandlerAdminAPI) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	serveGetWebhooks(w, r, h.service, chi.URLParam(r, "login"))
+}
+
+func (h *HandlerAdminAPI) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	serveDeleteWebhook(w, r, h.service, chi.URLParam(r, "login"))
+}
+
+func (h *HandlerAdminAPI) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	serveGetWebhookDeliveries(w, r, h.service, chi.URLParam(r, "login"))
+}
+
+func (h *HandlerAdminAPI) ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	serveReplayWebhookDelivery(w, r, h.service, chi.URLParam(r, "login"))
+}
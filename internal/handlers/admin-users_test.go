@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestAdminListUsers_Success(t *testing.T) {
+	ctrl, mockService, h := setupAdmin(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().ListUsers(gomock.Any(), "bob", 10, 20).Return([]models.UserInfo{{Login: "bob"}}, nil)
+
+	rr := adminRequest(t, h, http.MethodGet, "/users?q=bob&limit=10&offset=20", "", []string{models.RoleAdmin})
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status code %v, got %v", http.StatusOK, status)
+	}
+}
+
+func TestAdminListUsers_NotAdmin(t *testing.T) {
+	ctrl, _, h := setupAdmin(t)
+	defer ctrl.Finish()
+
+	rr := adminRequest(t, h, http.MethodGet, "/users", "", nil)
+
+	if status := rr.Code; status != http.StatusForbidden {
+		t.Errorf("Expected status code %v, got %v", http.StatusForbidden, status)
+	}
+}
+
+func TestAdminListUsers_InvalidLimit(t *testing.T) {
+	ctrl, _, h := setupAdmin(t)
+	defer ctrl.Finish()
+
+	rr := adminRequest(t, h, http.MethodGet, "/users?limit=0", "", []string{models.RoleAdmin})
+
+	if status := rr.Code; status != http.StatusBadRequest {
+		t.Errorf("Expected status code %v, got %v", http.StatusBadRequest, status)
+	}
+}
+
+func TestAdminLockUser_Success(t *testing.T) {
+	ctrl, mockService, h := setupAdmin(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().LockUser(gomock.Any(), "bob", "fraud").Return(nil)
+
+	rr := adminRequest(t, h, http.MethodPost, "/users/bob/lock", `{"reason":"fraud"}`, []string{models.RoleAdmin})
+
+	if status := rr.Code; status != http.StatusOK {
+		t.Errorf("Expected status code %v, got %v", http.StatusOK, status)
+	}
+}
+
+func TestAdminLockUser_NoSuchUser(t *testing.T) {
+	ctrl, mockService, h := setupAdmin(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().LockUser(gomock.Any(), "bob", "fraud").Return(customerror.ErrNoSuchUser)
+
+	rr := adminRequest(t, h, http.MethodPost, "/users/bob/lock", `{"reason":"fraud"}`, []string{models.RoleAdmin})
+
+	if status := rr.Code; status != http.StatusNotFound {
+		t.Errorf("Expected status code %v, got %v", http.StatusNotFound, status)
+	}
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/with0p/gophermart/internal/auth"
+	"github.com/with0p/gophermart/internal/logger"
+)
+
+const orderEventsHeartbeat = 15 * time.Second
+
+// GetOrderEvents streams order status changes as Server-Sent Events. Clients
+// resume with the Last-Event-ID header, or ?last_event_id= where they cannot
+// set headers.
+func (h *HandlerUserAPI) GetOrderEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := r.Context()
+	login, errLogin := auth.GetLoginFromRequestContext(ctx)
+	if errLogin != nil {
+		http.Error(w, errLogin.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var lastEventID int64
+	lastEventHeader := r.Header.Get("Last-Event-ID")
+	if lastEventHeader == "" {
+		lastEventHeader = r.URL.Query().Get("last_event_id")
+	}
+	if lastEventHeader != "" {
+		parsed, err := strconv.ParseInt(lastEventHeader, 10, 64)
+		if err != nil || parsed < 0 {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		lastEventID = parsed
+	}
+
+	events, err := h.service.SubscribeOrderEvents(ctx, login, lastEventID)
+	if err != nil {
+		logger.ErrorContext(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("content-type", "text/event-stream")
+	w.Header().Set("cache-control", "no-cache")
+	w.Header().Set("x-accel-buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(orderEventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				logger.ErrorContext(r.Context(), err)
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: order\ndata: %s\n\n", event.ID, data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/with0p/gophermart/internal/auth"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestGetOrderEvents_Stream(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders/events", nil)
+	req.Header.Set("Last-Event-ID", "41")
+	req = req.WithContext(context.WithValue(req.Context(), auth.LoginKey, "user1"))
+	rr := httptest.NewRecorder()
+
+	events := make(chan models.OrderEvent, 1)
+	events <- models.OrderEvent{ID: 42, OrderID: "12345678903", Status: "PROCESSED", Accrual: 50000, CreatedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)}
+	close(events)
+	mockService.EXPECT().SubscribeOrderEvents(gomock.Any(), "user1", int64(41)).Return(events, nil)
+
+	handler.GetOrderEvents(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "text/event-stream", rr.Header().Get("content-type"))
+	assert.Equal(t, "id: 42\nevent: order\ndata: {\"id\":42,\"number\":\"12345678903\",\"status\":\"PROCESSED\",\"accrual\":500,\"created_at\":\"2024-03-01T09:00:00Z\"}\n\n", rr.Body.String())
+}
+
+func TestGetOrderEvents_InvalidLastEventID(t *testing.T) {
+	ctrl, _, handler := setup(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders/events", nil)
+	req.Header.Set("Last-Event-ID", "abc")
+	req = req.WithContext(context.WithValue(req.Context(), auth.LoginKey, "user1"))
+	rr := httptest.NewRecorder()
+
+	handler.GetOrderEvents(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -20,7 +20,13 @@ func (h *HandlerUserAPI) GetUserOrders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	orders, errOrders := h.service.GetUserOrders(ctx, login)
+	q, errQuery := parseListQuery(r, orderSortFields, true)
+	if errQuery != nil {
+		http.Error(w, errQuery.Error(), http.StatusBadRequest)
+		return
+	}
+
+	orders, next, errOrders := h.service.GetUserOrders(ctx, login, q)
 
 	if errOrders != nil {
 		http.Error(w, errOrders.Error(), http.StatusInternalServerError)
@@ -39,6 +45,7 @@ func (h *HandlerUserAPI) GetUserOrders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	setNextLink(w, r, next)
 	w.Header().Set("content-type", "application/json")
 	w.WriteHeader(statusCode)
 	w.Write(response)
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -56,7 +57,7 @@ func TestGetUserOrders_ServiceError(t *testing.T) {
 	req = req.WithContext(ctx)
 	rr := httptest.NewRecorder()
 
-	mockService.EXPECT().GetUserOrders(gomock.Any(), "user1").Return(nil, errors.New("error"))
+	mockService.EXPECT().GetUserOrders(gomock.Any(), "user1", gomock.Any()).Return(nil, nil, errors.New("error"))
 
 	handler.GetUserOrders(rr, req)
 
@@ -70,7 +71,7 @@ func TestGetUserOrders_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	orders := []models.Order{
-		{OrderID: "1", Status: "completed", Accrual: 100, UploadDate: "1234"},
+		{OrderID: "1", Status: "completed", Accrual: 100, UploadDate: time.Date(2020, 12, 9, 13, 9, 57, 0, time.UTC)},
 	}
 	bodyBytes, err := json.Marshal(orders)
 	if err != nil {
@@ -83,7 +84,7 @@ func TestGetUserOrders_Success(t *testing.T) {
 	req = req.WithContext(ctx)
 	rr := httptest.NewRecorder()
 
-	mockService.EXPECT().GetUserOrders(gomock.Any(), "user1").Return(orders, nil)
+	mockService.EXPECT().GetUserOrders(gomock.Any(), "user1", gomock.Any()).Return(orders, nil, nil)
 
 	handler.GetUserOrders(rr, req)
 
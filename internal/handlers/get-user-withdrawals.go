@@ -20,7 +20,13 @@ func (h *HandlerUserAPI) GetUserWithdrawals(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	withdrawals, err := h.service.GetUserWithdrawals(ctx, login)
+	q, errQuery := parseListQuery(r, withdrawalSortFields, false)
+	if errQuery != nil {
+		http.Error(w, errQuery.Error(), http.StatusBadRequest)
+		return
+	}
+
+	withdrawals, next, err := h.service.GetUserWithdrawals(ctx, login, q)
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -39,6 +45,7 @@ func (h *HandlerUserAPI) GetUserWithdrawals(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	setNextLink(w, r, next)
 	w.Header().Set("content-type", "application/json")
 	w.WriteHeader(statusCode)
 	w.Write(response)
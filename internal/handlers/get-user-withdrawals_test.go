@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -41,7 +42,7 @@ func TestGetUserWithdrawals_NoContent(t *testing.T) {
 	req = req.WithContext(ctx)
 	rr := httptest.NewRecorder()
 
-	mockService.EXPECT().GetUserWithdrawals(gomock.Any(), "user1").Return(make([]models.Withdrawal, 0), nil)
+	mockService.EXPECT().GetUserWithdrawals(gomock.Any(), "user1", gomock.Any()).Return(make([]models.Withdrawal, 0), nil, nil)
 
 	handler.GetUserWithdrawals(rr, req)
 
@@ -74,7 +75,7 @@ func TestGetUserWithdrawals_ServiceError(t *testing.T) {
 	req = req.WithContext(ctx)
 	rr := httptest.NewRecorder()
 
-	mockService.EXPECT().GetUserWithdrawals(gomock.Any(), "user1").Return(nil, errors.New("service error"))
+	mockService.EXPECT().GetUserWithdrawals(gomock.Any(), "user1", gomock.Any()).Return(nil, nil, errors.New("service error"))
 
 	handler.GetUserWithdrawals(rr, req)
 
@@ -88,7 +89,7 @@ func TestGetUserWithdrawals_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	withdrawals := []models.Withdrawal{
-		{OrderID: "2377225624", Sum: 500, ProcessedAt: "2020-12-09T16:09:57+03:00"},
+		{OrderID: "2377225624", Sum: 500, ProcessedAt: time.Date(2020, 12, 9, 13, 9, 57, 0, time.UTC)},
 	}
 	bodyBytes, err := json.Marshal(withdrawals)
 	if err != nil {
@@ -101,7 +102,7 @@ func TestGetUserWithdrawals_Success(t *testing.T) {
 	req = req.WithContext(ctx)
 	rr := httptest.NewRecorder()
 
-	mockService.EXPECT().GetUserWithdrawals(gomock.Any(), "user1").Return(withdrawals, nil)
+	mockService.EXPECT().GetUserWithdrawals(gomock.Any(), "user1", gomock.Any()).Return(withdrawals, nil, nil)
 
 	handler.GetUserWithdrawals(rr, req)
 
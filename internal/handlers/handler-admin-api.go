@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/with0p/gophermart/internal/auth"
+	"github.com/with0p/gophermart/internal/models"
+	"github.com/with0p/gophermart/internal/service"
+)
+
+type HandlerAdminAPI struct {
+	service       service.AdminService
+	authenticator *auth.Authenticator
+}
+
+func NewHandlerAdminAPI(currentService service.AdminService, authenticator *auth.Authenticator) *HandlerAdminAPI {
+	return &HandlerAdminAPI{service: currentService, authenticator: authenticator}
+}
+
+// GetHandlerAdminAPIRouter is meant to be mounted at /api/admin.
+func (h HandlerAdminAPI) GetHandlerAdminAPIRouter() *chi.Mux {
+	mux := chi.NewRouter()
+	mux.Get(`/users`, h.admin(h.ListUsers))
+	mux.Get(`/users/{login}/orders`, h.admin(h.GetUserOrders))
+	mux.Get(`/users/{login}/withdrawals`, h.admin(h.GetUserWithdrawals))
+	mux.Get(`/users/{login}/balance`, h.admin(h.GetUserBalance))
+	mux.Get(`/users/{login}/ledger`, h.admin(h.GetUserLedger))
+	mux.Post(`/users/{login}/adjustments`, h.admin(h.AdjustBalance))
+	mux.Post(`/users/{login}/lock`, h.admin(h.LockUser))
+	mux.Post(`/users/{login}/unlock`, h.admin(h.UnlockUser))
+	mux.Put(`/users/{login}/roles`, h.admin(h.SetUserRoles))
+	mux.Post(`/users/{login}/webhooks`, h.admin(h.CreateWebhook))
+	mux.Get(`/users/{login}/webhooks`, h.admin(h.GetWebhooks))
+	mux.Delete(`/users/{login}/webhooks/{id}`, h.admin(h.DeleteWebhook))
+	mux.Get(`/users/{login}/webhooks/{id}/deliveries`, h.admin(h.GetWebhookDeliveries))
+	mux.Post(`/users/{login}/webhooks/deliveries/{id}/replay`, h.admin(h.ReplayWebhookDelivery))
+	mux.Post(`/orders/{number}/recheck`, h.admin(h.RecheckOrder))
+	return mux
+}
+
+func (h HandlerAdminAPI) admin(next http.HandlerFunc) http.HandlerFunc {
+	return h.authenticator.UseValidateAuth(h.authenticator.UseRequireRole(models.RoleAdmin, next))
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value any) {
+	response, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(response)
+}
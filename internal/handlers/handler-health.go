@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/with0p/gophermart/internal/models"
+	"github.com/with0p/gophermart/internal/service"
+)
+
+type HandlerHealth struct {
+	service  service.Service
+	draining atomic.Bool
+}
+
+func NewHandlerHealth(currentService service.Service) *HandlerHealth {
+	return &HandlerHealth{service: currentService}
+}
+
+// Drain makes readiness fail from now on, so that load balancers stop
+// sending traffic before the server shuts down.
+func (h *HandlerHealth) Drain() {
+	h.draining.Store(true)
+}
+
+// Liveness only tells that the process serves HTTP.
+func (h *HandlerHealth) Liveness(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": models.ReadinessOK})
+}
+
+func (h *HandlerHealth) Readiness(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "draining"})
+		return
+	}
+
+	readiness := h.service.CheckReadiness(r.Context())
+
+	statusCode := http.StatusOK
+	if readiness.Status == models.ReadinessUnavailable {
+		statusCode = http.StatusServiceUnavailable
+	}
+	writeJSON(w, statusCode, readiness)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/with0p/gophermart/internal/mock"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func setupHealth(t *testing.T) (*mock.MockService, *HandlerHealth) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockService(ctrl)
+	return mockService, NewHandlerHealth(mockService)
+}
+
+func TestReadiness_Degraded(t *testing.T) {
+	mockService, h := setupHealth(t)
+	mockService.EXPECT().CheckReadiness(gomock.Any()).Return(models.Readiness{
+		Status:   models.ReadinessDegraded,
+		Database: models.CheckResult{Status: models.CheckOK},
+		Accrual:  models.CheckResult{Status: models.CheckUnreachable, Error: "connection refused"},
+		Queue:    models.CheckResult{Status: models.CheckOK},
+	})
+
+	rr := httptest.NewRecorder()
+	h.Readiness(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"status":"degraded","database":{"status":"ok"},"accrual":{"status":"unreachable","error":"connection refused"},"queue":{"status":"ok"}}`, rr.Body.String())
+}
+
+func TestReadiness_DatabaseDown(t *testing.T) {
+	mockService, h := setupHealth(t)
+	mockService.EXPECT().CheckReadiness(gomock.Any()).Return(models.Readiness{
+		Status:   models.ReadinessUnavailable,
+		Database: models.CheckResult{Status: models.CheckDown},
+	})
+
+	rr := httptest.NewRecorder()
+	h.Readiness(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
+
+func TestReadiness_Draining(t *testing.T) {
+	_, h := setupHealth(t)
+	h.Drain()
+
+	rr := httptest.NewRecorder()
+	h.Readiness(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+	rr = httptest.NewRecorder()
+	h.Liveness(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
@@ -3,27 +3,44 @@ package handlers
 import (
 	"github.com/go-chi/chi"
 	"github.com/with0p/gophermart/internal/auth"
-	"github.com/with0p/gophermart/internal/models"
+	"github.com/with0p/gophermart/internal/metrics"
 	"github.com/with0p/gophermart/internal/service"
+	"github.com/with0p/gophermart/internal/tracing"
 )
 
 type HandlerUserAPI struct {
-	service service.Service
-	queue   chan models.OrderID
+	service       service.Service
+	authenticator *auth.Authenticator
 }
 
-func NewHandlerUserAPI(currentService service.Service, queue chan models.OrderID) *HandlerUserAPI {
-	return &HandlerUserAPI{service: currentService, queue: queue}
+func NewHandlerUserAPI(currentService service.Service, authenticator *auth.Authenticator) *HandlerUserAPI {
+	return &HandlerUserAPI{service: currentService, authenticator: authenticator}
 }
 
 func (h HandlerUserAPI) GetHandlerUserAPIRouter() *chi.Mux {
 	mux := chi.NewRouter()
+	mux.Use(tracing.Middleware)
+	mux.Use(UseRequestID)
+	mux.Use(UseAccessLog)
+	mux.Use(metrics.Middleware)
 	mux.Post(`/api/user/register`, h.RegisterUser)
 	mux.Post(`/api/user/login`, h.LoginUser)
-	mux.Post(`/api/user/orders`, auth.UseValidateAuth(h.AddOrder))
-	mux.Get(`/api/user/orders`, auth.UseValidateAuth(h.GetUserOrders))
-	mux.Post(`/api/user/balance/withdraw`, auth.UseValidateAuth(h.MakeWithdrawal))
-	mux.Get(`/api/user/balance`, auth.UseValidateAuth(h.GetUserBalance))
-	mux.Get(`/api/user/withdrawals`, auth.UseValidateAuth(h.GetUserWithdrawals))
+	mux.Post(`/api/user/token/refresh`, h.RefreshToken)
+	mux.Post(`/api/user/logout`, h.authenticator.UseValidateAuth(h.LogoutUser))
+	mux.Post(`/api/user/logout-all`, h.authenticator.UseValidateAuth(h.LogoutAllUser))
+	mux.Post(`/api/user/orders`, h.authenticator.UseValidateAuth(h.AddOrder))
+	mux.Get(`/api/user/orders`, h.authenticator.UseValidateAuth(h.GetUserOrders))
+	mux.Post(`/api/user/orders/batch`, h.authenticator.UseValidateAuth(h.AddOrdersBatch))
+	mux.Get(`/api/user/orders/events`, h.authenticator.UseValidateAuth(h.GetOrderEvents))
+	mux.Post(`/api/user/balance/withdraw`, h.authenticator.UseValidateAuth(h.UseIdempotency(h.MakeWithdrawal)))
+	mux.Get(`/api/user/balance`, h.authenticator.UseValidateAuth(h.GetUserBalance))
+	mux.Get(`/api/user/withdrawals`, h.authenticator.UseValidateAuth(h.GetUserWithdrawals))
+	mux.Get(`/api/user/preferences`, h.authenticator.UseValidateAuth(h.GetUserPreferences))
+	mux.Put(`/api/user/preferences`, h.authenticator.UseValidateAuth(h.SetUserPreferences))
+	mux.Post(`/api/user/webhooks`, h.authenticator.UseValidateAuth(h.CreateWebhook))
+	mux.Get(`/api/user/webhooks`, h.authenticator.UseValidateAuth(h.GetWebhooks))
+	mux.Delete(`/api/user/webhooks/{id}`, h.authenticator.UseValidateAuth(h.DeleteWebhook))
+	mux.Get(`/api/user/webhooks/{id}/deliveries`, h.authenticator.UseValidateAuth(h.GetWebhookDeliveries))
+	mux.Post(`/api/user/webhooks/deliveries/{id}/replay`, h.authenticator.UseValidateAuth(h.ReplayWebhookDelivery))
 	return mux
 }
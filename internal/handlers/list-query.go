@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+	"github.com/with0p/gophermart/internal/utils"
+)
+
+var (
+	orderSortFields      = []string{"uploaded_at", "accrual"}
+	withdrawalSortFields = []string{"processed_at", "sum"}
+)
+
+// cursorTimeLayouts cover how PostgreSQL prints a timestamptz as text; the
+// fraction is accepted without being in the layout.
+var cursorTimeLayouts = []string{
+	"2006-01-02 15:04:05-07",
+	"2006-01-02 15:04:05-07:00",
+	"2006-01-02 15:04:05-07:00:00",
+}
+
+// parseListQuery reads ?limit=&cursor=&sort=&from=&to=&tz= and, for orders,
+// ?status=. The cursor must come from a listing with the same sort. Without
+// limit or cursor every row is listed, as before paging was added.
+func parseListQuery(r *http.Request, sortFields []string, withStatus bool) (models.ListQuery, error) {
+	values := r.URL.Query()
+	q := models.ListQuery{Sort: "-" + sortFields[0]}
+	if values.Get("cursor") != "" {
+		q.Limit = models.DefaultPageSize
+	}
+
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > models.MaxPageSize {
+			return q, fmt.Errorf("limit must be between 1 and %d", models.MaxPageSize)
+		}
+		q.Limit = limit
+	}
+
+	if value := values.Get("sort"); value != "" {
+		if !containsString(sortFields, strings.TrimPrefix(value, "-")) {
+			return q, fmt.Errorf("sort must be one of %s, optionally prefixed with \"-\"", strings.Join(sortFields, ", "))
+		}
+		q.Sort = value
+	}
+
+	if value := values.Get("cursor"); value != "" {
+		cursor, err := models.ParseCursor(value)
+		if err != nil {
+			return q, err
+		}
+		if cursor.Sort != q.Sort {
+			return q, errors.New("cursor was issued for another sort")
+		}
+		if !validCursorValue(strings.TrimPrefix(q.Sort, "-"), cursor.Value) {
+			return q, customerror.ErrInvalidCursor
+		}
+		q.Cursor = cursor
+	}
+
+	for name, target := range map[string]**time.Time{"from": &q.From, "to": &q.To} {
+		if value := values.Get(name); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return q, fmt.Errorf("%s must be an RFC 3339 time", name)
+			}
+			*target = &parsed
+		}
+	}
+
+	if value := values.Get("tz"); value != "" {
+		if _, err := utils.LoadLocation(value); err != nil {
+			return q, fmt.Errorf("%w %q", err, value)
+		}
+		q.TimeZone = value
+	}
+
+	if withStatus {
+		for _, value := range values["status"] {
+			for _, status := range strings.Split(value, ",") {
+				switch models.OrderStatus(status) {
+				case models.StatusNew, models.StatusProcessing, models.StatusInvalid, models.StatusProcessed:
+					q.Statuses = append(q.Statuses, models.OrderStatus(status))
+				default:
+					return q, fmt.Errorf("unknown status %q", status)
+				}
+			}
+		}
+	}
+
+	return q, nil
+}
+
+// setNextLink points the Link header at the next page, keeping the other
+// query parameters of the request.
+func setNextLink(w http.ResponseWriter, r *http.Request, next *models.Cursor) {
+	if next == nil {
+		return
+	}
+
+	nextURL := *r.URL
+	values := nextURL.Query()
+	values.Set("cursor", next.String())
+	nextURL.RawQuery = values.Encode()
+
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", nextURL.RequestURI()))
+}
+
+// validCursorValue checks the value against the type of the sort field, so
+// that a tampered cursor is refused here rather than failing the SQL cast.
+func validCursorValue(field string, value string) bool {
+	switch field {
+	case "accrual", "sum":
+		_, err := strconv.ParseFloat(value, 64)
+		return err == nil
+	default:
+		for _, layout := range cursorTimeLayouts {
+			if _, err := time.Parse(layout, value); err == nil {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
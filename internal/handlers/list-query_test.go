@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestParseListQuery_Defaults(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders", nil)
+
+	q, err := parseListQuery(req, orderSortFields, true)
+	require.NoError(t, err)
+
+	// clients that do not page get every row
+	assert.Equal(t, 0, q.Limit)
+	assert.Equal(t, "-uploaded_at", q.Sort)
+	assert.Nil(t, q.Cursor)
+}
+
+func TestParseListQuery_CursorWithoutLimit(t *testing.T) {
+	cursor := models.Cursor{Sort: "-uploaded_at", Value: "2024-02-01 10:00:00.123456+00", ID: "12345678903"}
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders?cursor="+cursor.String(), nil)
+
+	q, err := parseListQuery(req, orderSortFields, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.DefaultPageSize, q.Limit)
+	assert.Equal(t, &cursor, q.Cursor)
+}
+
+func TestParseListQuery_Filters(t *testing.T) {
+	cursor := models.Cursor{Sort: "accrual", Value: "10.00", ID: "12345678903"}
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders?limit=20&sort=accrual&status=NEW,PROCESSED&from=2024-01-01T00:00:00Z&cursor="+cursor.String(), nil)
+
+	q, err := parseListQuery(req, orderSortFields, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, 20, q.Limit)
+	assert.Equal(t, []models.OrderStatus{models.StatusNew, models.StatusProcessed}, q.Statuses)
+	require.NotNil(t, q.From)
+	assert.Nil(t, q.To)
+	assert.Equal(t, &cursor, q.Cursor)
+}
+
+func TestParseListQuery_Invalid(t *testing.T) {
+	other := models.Cursor{Sort: "-uploaded_at", ID: "12345678903"}
+	badTime := models.Cursor{Sort: "-uploaded_at", Value: "yesterday", ID: "12345678903"}
+	badAmount := models.Cursor{Sort: "accrual", Value: "1e", ID: "12345678903"}
+	amountForTime := models.Cursor{Sort: "uploaded_at", Value: "10.00", ID: "12345678903"}
+	for _, query := range []string{
+		"limit=0",
+		"limit=100000",
+		"sort=status",
+		"status=DONE",
+		"from=yesterday",
+		"cursor=not-a-cursor",
+		"sort=accrual&cursor=" + other.String(),
+		"cursor=" + badTime.String(),
+		"sort=accrual&cursor=" + badAmount.String(),
+		"sort=uploaded_at&cursor=" + amountForTime.String(),
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/user/orders?"+query, nil)
+		_, err := parseListQuery(req, orderSortFields, true)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestSetNextLink(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders?limit=2&status=NEW", nil)
+	rr := httptest.NewRecorder()
+
+	next := &models.Cursor{Sort: "-uploaded_at", Value: "2024-01-01 00:00:00+00", ID: "12345678903"}
+	setNextLink(rr, req, next)
+
+	link := rr.Header().Get("Link")
+	assert.True(t, strings.HasPrefix(link, "</api/user/orders?"))
+	assert.Contains(t, link, "cursor="+next.String())
+	assert.Contains(t, link, "limit=2")
+	assert.True(t, strings.HasSuffix(link, `>; rel="next"`))
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"net/http"
 
-	"github.com/with0p/gophermart/internal/auth"
 	"github.com/with0p/gophermart/internal/custom-error"
 	"github.com/with0p/gophermart/internal/models"
 )
@@ -28,19 +27,17 @@ func (h *HandlerUserAPI) LoginUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	statusCode := http.StatusOK
-
 	serviceErr := h.service.AuthenticateUser(r.Context(), user.Login, user.Password)
 	if serviceErr != nil {
 		if errors.Is(serviceErr, customerror.ErrNoSuchUser) {
-			statusCode = http.StatusUnauthorized
+			w.WriteHeader(http.StatusUnauthorized)
+		} else if errors.Is(serviceErr, customerror.ErrUserLocked) {
+			http.Error(w, serviceErr.Error(), http.StatusForbidden)
 		} else {
 			http.Error(w, serviceErr.Error(), http.StatusBadRequest)
-			return
 		}
+		return
 	}
 
-	auth.SetAuth(r, w, user.Login)
-
-	w.WriteHeader(statusCode)
+	h.writeTokens(w, r, user.Login)
 }
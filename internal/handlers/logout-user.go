@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/with0p/gophermart/internal/auth"
+	"github.com/with0p/gophermart/internal/logger"
+)
+
+func (h *HandlerUserAPI) LogoutUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Not a POST requests", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	sessionID, errSession := auth.GetSessionIDFromRequestContext(ctx)
+	if errSession != nil {
+		http.Error(w, errSession.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.authenticator.Logout(ctx, w, sessionID); err != nil {
+		logger.ErrorContext(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *HandlerUserAPI) LogoutAllUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Not a POST requests", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx := r.Context()
+	login, errLogin := auth.GetLoginFromRequestContext(ctx)
+	if errLogin != nil {
+		http.Error(w, errLogin.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.authenticator.LogoutAll(ctx, w, login); err != nil {
+		logger.ErrorContext(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
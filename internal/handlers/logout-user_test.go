@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/with0p/gophermart/internal/auth"
+)
+
+func TestLogoutUser_Success(t *testing.T) {
+	ctrl, _, mockSessions, handler := setupWithSessions(t)
+	defer ctrl.Finish()
+
+	sessionID := uuid.New()
+	mockSessions.EXPECT().RevokeSession(gomock.Any(), sessionID).Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/logout", nil)
+	ctx := context.WithValue(req.Context(), auth.SessionKey, sessionID)
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	handler.LogoutUser(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestLogoutUser_NoSession(t *testing.T) {
+	ctrl, _, _, handler := setupWithSessions(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/logout", nil)
+	rr := httptest.NewRecorder()
+
+	handler.LogoutUser(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
+
+func TestLogoutAllUser_Success(t *testing.T) {
+	ctrl, _, mockSessions, handler := setupWithSessions(t)
+	defer ctrl.Finish()
+
+	mockSessions.EXPECT().RevokeUserSessions(gomock.Any(), "user1").Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/logout-all", nil)
+	ctx := context.WithValue(req.Context(), auth.LoginKey, "user1")
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	handler.LogoutAllUser(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestLogoutAllUser_StoreError(t *testing.T) {
+	ctrl, _, mockSessions, handler := setupWithSessions(t)
+	defer ctrl.Finish()
+
+	mockSessions.EXPECT().RevokeUserSessions(gomock.Any(), "user1").Return(errors.New("error"))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/logout-all", nil)
+	ctx := context.WithValue(req.Context(), auth.LoginKey, "user1")
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	handler.LogoutAllUser(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
@@ -13,7 +13,7 @@ import (
 
 type OrderWithdrawalData struct {
 	OrderID models.OrderID `json:"order"`
-	Sum     float32        `json:"sum"`
+	Sum     models.Amount  `json:"sum"`
 }
 
 func (h *HandlerUserAPI) MakeWithdrawal(w http.ResponseWriter, r *http.Request) {
@@ -48,13 +48,15 @@ func (h *HandlerUserAPI) MakeWithdrawal(w http.ResponseWriter, r *http.Request)
 	switch {
 	case errW == nil:
 		statusCode = http.StatusOK
-	case errors.Is(errW, customerror.ErrWrongOrderFormat):
+	case errors.Is(errW, customerror.ErrWrongOrderFormat), errors.Is(errW, customerror.ErrInvalidWithdrawalSum):
 		statusCode = http.StatusUnprocessableEntity
 	case errors.Is(errW, customerror.ErrInsufficientBalance):
 		statusCode = http.StatusPaymentRequired
+	case errors.Is(errW, customerror.ErrWithdrawalAlreadyExists):
+		statusCode = http.StatusConflict
 	default:
 		statusCode = http.StatusInternalServerError
-		logger.Error(errW)
+		logger.ErrorContext(ctx, errW, "order_id", orderWithdrawal.OrderID, "sum", orderWithdrawal.Sum)
 	}
 
 	w.WriteHeader(statusCode)
@@ -20,7 +20,7 @@ func TestMakeWithdrawal_AuthError(t *testing.T) {
 
 	withdrawalData := OrderWithdrawalData{
 		OrderID: "2377225624",
-		Sum:     500,
+		Sum:     50000,
 	}
 	reqBody, err := json.Marshal(withdrawalData)
 	if err != nil {
@@ -59,7 +59,7 @@ func TestMakeWithdrawal_ServiceError_InsufficientBalance(t *testing.T) {
 
 	withdrawalData := OrderWithdrawalData{
 		OrderID: "2377225624",
-		Sum:     500,
+		Sum:     50000,
 	}
 	reqBody, err := json.Marshal(withdrawalData)
 	if err != nil {
@@ -72,7 +72,7 @@ func TestMakeWithdrawal_ServiceError_InsufficientBalance(t *testing.T) {
 	req = req.WithContext(ctx)
 	rr := httptest.NewRecorder()
 
-	mockService.EXPECT().MakeWithdrawal(gomock.Any(), "user1", models.OrderID("2377225624"), float32(500.0)).Return(customerror.ErrInsufficientBalance)
+	mockService.EXPECT().MakeWithdrawal(gomock.Any(), "user1", models.OrderID("2377225624"), models.Amount(50000)).Return(customerror.ErrInsufficientBalance)
 
 	handler.MakeWithdrawal(rr, req)
 
@@ -87,7 +87,7 @@ func TestMakeWithdrawal_ServiceError(t *testing.T) {
 
 	withdrawalData := OrderWithdrawalData{
 		OrderID: "2377225624",
-		Sum:     500,
+		Sum:     50000,
 	}
 	reqBody, err := json.Marshal(withdrawalData)
 	if err != nil {
@@ -100,7 +100,7 @@ func TestMakeWithdrawal_ServiceError(t *testing.T) {
 	req = req.WithContext(ctx)
 	rr := httptest.NewRecorder()
 
-	mockService.EXPECT().MakeWithdrawal(gomock.Any(), "user1", models.OrderID("2377225624"), float32(500.0)).Return(customerror.ErrWrongOrderFormat)
+	mockService.EXPECT().MakeWithdrawal(gomock.Any(), "user1", models.OrderID("2377225624"), models.Amount(50000)).Return(customerror.ErrWrongOrderFormat)
 
 	handler.MakeWithdrawal(rr, req)
 
@@ -114,7 +114,7 @@ func TestMakeWithdrawal_Success(t *testing.T) {
 
 	withdrawalData := OrderWithdrawalData{
 		OrderID: "2377225624",
-		Sum:     500,
+		Sum:     50000,
 	}
 	reqBody, err := json.Marshal(withdrawalData)
 	if err != nil {
@@ -127,7 +127,7 @@ func TestMakeWithdrawal_Success(t *testing.T) {
 	req = req.WithContext(ctx)
 	rr := httptest.NewRecorder()
 
-	mockService.EXPECT().MakeWithdrawal(gomock.Any(), "user1", models.OrderID("2377225624"), float32(500.0)).Return(nil)
+	mockService.EXPECT().MakeWithdrawal(gomock.Any(), "user1", models.OrderID("2377225624"), models.Amount(50000)).Return(nil)
 
 	handler.MakeWithdrawal(rr, req)
 
@@ -135,3 +135,59 @@ func TestMakeWithdrawal_Success(t *testing.T) {
 		t.Errorf("Expected status code %v, got %v", http.StatusOK, status)
 	}
 }
+
+func TestMakeWithdrawal_AlreadyExists(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	withdrawalData := OrderWithdrawalData{
+		OrderID: "2377225624",
+		Sum:     50000,
+	}
+	reqBody, err := json.Marshal(withdrawalData)
+	if err != nil {
+		t.Fatalf("Failed to marshal withdrawal data: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/withdrawals", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), auth.LoginKey, "user1")
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	mockService.EXPECT().MakeWithdrawal(gomock.Any(), "user1", models.OrderID("2377225624"), models.Amount(50000)).Return(customerror.ErrWithdrawalAlreadyExists)
+
+	handler.MakeWithdrawal(rr, req)
+
+	if status := rr.Code; status != http.StatusConflict {
+		t.Errorf("Expected status code %v, got %v", http.StatusConflict, status)
+	}
+}
+
+func TestMakeWithdrawal_InvalidSum(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	withdrawalData := OrderWithdrawalData{
+		OrderID: "2377225624",
+		Sum:     -50000,
+	}
+	reqBody, err := json.Marshal(withdrawalData)
+	if err != nil {
+		t.Fatalf("Failed to marshal withdrawal data: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/withdrawals", bytes.NewReader(reqBody))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), auth.LoginKey, "user1")
+	req = req.WithContext(ctx)
+	rr := httptest.NewRecorder()
+
+	mockService.EXPECT().MakeWithdrawal(gomock.Any(), "user1", models.OrderID("2377225624"), models.Amount(-50000)).Return(customerror.ErrInvalidWithdrawalSum)
+
+	handler.MakeWithdrawal(rr, req)
+
+	if status := rr.Code; status != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %v, got %v", http.StatusUnprocessableEntity, status)
+	}
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/with0p/gophermart/internal/auth"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/logger"
+)
+
+type RefreshTokenData struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (h *HandlerUserAPI) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Not a POST requests", http.StatusMethodNotAllowed)
+		return
+	}
+
+	refreshToken := auth.RefreshTokenFromRequest(r)
+	if r.Header.Get("content-type") == "application/json" {
+		var data RefreshTokenData
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if data.RefreshToken != "" {
+			refreshToken = data.RefreshToken
+		}
+	}
+
+	if refreshToken == "" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := h.authenticator.Refresh(r.Context(), w, refreshToken)
+	switch {
+	case err == nil:
+		writeTokenPair(w, tokens)
+	case errors.Is(err, customerror.ErrInvalidRefreshToken), errors.Is(err, customerror.ErrRefreshTokenReused):
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+	default:
+		logger.ErrorContext(r.Context(), err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+)
+
+func TestRefreshToken_MethodNotAllowed(t *testing.T) {
+	ctrl, _, _, handler := setupWithSessions(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/token/refresh", nil)
+	rr := httptest.NewRecorder()
+
+	handler.RefreshToken(rr, req)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rr.Code)
+}
+
+func TestRefreshToken_Missing(t *testing.T) {
+	ctrl, _, _, handler := setupWithSessions(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/token/refresh", nil)
+	rr := httptest.NewRecorder()
+
+	handler.RefreshToken(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestRefreshToken_Reused(t *testing.T) {
+	ctrl, _, mockSessions, handler := setupWithSessions(t)
+	defer ctrl.Finish()
+
+	mockSessions.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.Nil, "", customerror.ErrRefreshTokenReused)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/token/refresh", strings.NewReader(`{"refresh_token":"old"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rr := httptest.NewRecorder()
+
+	handler.RefreshToken(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+}
+
+func TestRefreshToken_Success(t *testing.T) {
+	ctrl, _, mockSessions, handler := setupWithSessions(t)
+	defer ctrl.Finish()
+
+	mockSessions.EXPECT().RotateRefreshToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.New(), "user1", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/token/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "current"})
+	rr := httptest.NewRecorder()
+
+	handler.RefreshToken(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.True(t, strings.HasPrefix(rr.Header().Get("Authorization"), "Bearer "))
+	assert.Contains(t, rr.Body.String(), "refresh_token")
+}
@@ -5,7 +5,6 @@ import (
 	"errors"
 	"net/http"
 
-	"github.com/with0p/gophermart/internal/auth"
 	"github.com/with0p/gophermart/internal/custom-error"
 	"github.com/with0p/gophermart/internal/models"
 )
@@ -28,19 +27,15 @@ func (h *HandlerUserAPI) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	statusCode := http.StatusOK
-
 	serviceErr := h.service.RegisterUser(r.Context(), user.Login, user.Password)
 	if serviceErr != nil {
 		if errors.Is(serviceErr, customerror.ErrUniqueKeyConstrantViolation) {
-			statusCode = http.StatusConflict
+			w.WriteHeader(http.StatusConflict)
 		} else {
 			http.Error(w, serviceErr.Error(), http.StatusBadRequest)
-			return
 		}
+		return
 	}
 
-	auth.SetAuth(r, w, user.Login)
-
-	w.WriteHeader(statusCode)
+	h.writeTokens(w, r, user.Login)
 }
@@ -1,24 +1,58 @@
 package handlers
 
 import (
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/with0p/gophermart/internal/auth"
 	"github.com/with0p/gophermart/internal/mock"
-	"github.com/with0p/gophermart/internal/models"
 )
 
 func setup(t *testing.T) (*gomock.Controller, *mock.MockService, *HandlerUserAPI) {
-	ctrl := gomock.NewController(t)
-	mockService := mock.NewMockService(ctrl)
-	h := &HandlerUserAPI{service: mockService}
+	ctrl, mockService, mockSessions, h := setupWithSessions(t)
+	mockSessions.EXPECT().CreateSession(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.New(), nil).AnyTimes()
 	return ctrl, mockService, h
 }
 
-func setupWithQueue(t *testing.T) (*gomock.Controller, *mock.MockService, *HandlerUserAPI) {
+func setupWithSessions(t *testing.T) (*gomock.Controller, *mock.MockService, *mock.MockSessionStore, *HandlerUserAPI) {
 	ctrl := gomock.NewController(t)
 	mockService := mock.NewMockService(ctrl)
-	queue := make(chan models.OrderID, 1)
-	h := &HandlerUserAPI{service: mockService, queue: queue}
+	mockSessions := mock.NewMockSessionStore(ctrl)
+	mockSessions.EXPECT().GetUserRoles(gomock.Any(), gomock.Any()).Return([]string{}, nil).AnyTimes()
+	keys, err := auth.NewSecretKeySet("test_secret")
+	if err != nil {
+		t.Fatalf("Failed to create key set: %v", err)
+	}
+	h := &HandlerUserAPI{service: mockService, authenticator: auth.NewAuthenticator(keys, mockSessions, auth.Options{CSRFProtection: true})}
+	return ctrl, mockService, mockSessions, h
+}
+
+func setupAdmin(t *testing.T) (*gomock.Controller, *mock.MockAdminService, *HandlerAdminAPI) {
+	ctrl := gomock.NewController(t)
+	mockService := mock.NewMockAdminService(ctrl)
+	mockSessions := mock.NewMockSessionStore(ctrl)
+	mockSessions.EXPECT().IsSessionActive(gomock.Any(), gomock.Any()).Return(true, nil).AnyTimes()
+	keys, err := auth.NewSecretKeySet("test_secret")
+	if err != nil {
+		t.Fatalf("Failed to create key set: %v", err)
+	}
+	h := &HandlerAdminAPI{service: mockService, authenticator: auth.NewAuthenticator(keys, mockSessions, auth.Options{CSRFProtection: true})}
 	return ctrl, mockService, h
 }
+
+func adminRequest(t *testing.T, h *HandlerAdminAPI, method string, target string, body string, roles []string) *httptest.ResponseRecorder {
+	token, err := h.authenticator.GenerateJWT("admin1", uuid.New(), roles, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	h.GetHandlerAdminAPIRouter().ServeHTTP(rr, req)
+	return rr
+}
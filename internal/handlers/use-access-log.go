@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/with0p/gophermart/internal/logger"
+	"github.com/with0p/gophermart/internal/routing"
+)
+
+// UseAccessLog writes one entry per request once it has been served.
+func UseAccessLog(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := routing.WrapWriter(w, r)
+
+		next.ServeHTTP(ww, r)
+
+		route, _ := routing.Pattern(r)
+		logger.InfoContext(r.Context(), "request",
+			"method", r.Method,
+			"route", route,
+			"status", routing.Status(ww),
+			"duration", time.Since(start),
+			"bytes", ww.BytesWritten(),
+		)
+	})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/with0p/gophermart/internal/auth"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/logger"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+const idempotencyKeyHeader = "Idempotency-Key"
+
+type recordingResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *recordingResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *recordingResponseWriter) Write(data []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.statusCode = http.StatusOK
+	}
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// UseIdempotency replays the stored response when a request is retried with
+// the same Idempotency-Key. Server errors are not stored, so such requests
+// can be retried under the same key.
+func (h *HandlerUserAPI) UseIdempotency(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(idempotencyKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		login, errLogin := auth.GetLoginFromRequestContext(ctx)
+		if errLogin != nil {
+			http.Error(w, errLogin.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		body, errRead := io.ReadAll(r.Body)
+		defer r.Body.Close()
+		if errRead != nil {
+			http.Error(w, errRead.Error(), http.StatusInternalServerError)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(r.Method + " " + r.URL.Path + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		stored, err := h.service.BeginIdempotentRequest(ctx, login, key, requestHash)
+		switch {
+		case errors.Is(err, customerror.ErrIdempotencyKeyMismatch):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		case errors.Is(err, customerror.ErrIdempotencyKeyInProgress):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			logger.ErrorContext(r.Context(), err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		case stored != nil:
+			w.Header().Set("Idempotent-Replayed", "true")
+			w.WriteHeader(stored.StatusCode)
+			w.Write(stored.Body)
+			return
+		}
+
+		recorder := &recordingResponseWriter{ResponseWriter: w}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.statusCode == 0 {
+			recorder.statusCode = http.StatusOK
+		}
+		if recorder.statusCode >= http.StatusInternalServerError {
+			if err := h.service.ReleaseIdempotentRequest(ctx, login, key); err != nil {
+				logger.ErrorContext(r.Context(), err)
+			}
+			return
+		}
+
+		response := models.IdempotentResponse{StatusCode: recorder.statusCode, Body: recorder.body.Bytes()}
+		if err := h.service.CompleteIdempotentRequest(ctx, login, key, response); err != nil {
+			logger.ErrorContext(r.Context(), err)
+		}
+	})
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/with0p/gophermart/internal/auth"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func newIdempotentRequest(key string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/user/balance/withdraw", strings.NewReader(`{"order":"2377225624","sum":500}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotencyKeyHeader, key)
+	ctx := context.WithValue(req.Context(), auth.LoginKey, "user1")
+	return req.WithContext(ctx)
+}
+
+func TestUseIdempotency_NoKey(t *testing.T) {
+	ctrl, _, handler := setup(t)
+	defer ctrl.Finish()
+
+	called := false
+	next := func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}
+
+	req := newIdempotentRequest("")
+	rr := httptest.NewRecorder()
+
+	handler.UseIdempotency(next)(rr, req)
+
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestUseIdempotency_FirstRequest(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().BeginIdempotentRequest(gomock.Any(), "user1", "key1", gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().CompleteIdempotentRequest(gomock.Any(), "user1", "key1", models.IdempotentResponse{StatusCode: http.StatusPaymentRequired}).Return(nil)
+
+	next := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusPaymentRequired)
+	}
+
+	req := newIdempotentRequest("key1")
+	rr := httptest.NewRecorder()
+
+	handler.UseIdempotency(next)(rr, req)
+
+	assert.Equal(t, http.StatusPaymentRequired, rr.Code)
+}
+
+func TestUseIdempotency_Replay(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	stored := &models.IdempotentResponse{StatusCode: http.StatusOK, Body: []byte("done")}
+	mockService.EXPECT().BeginIdempotentRequest(gomock.Any(), "user1", "key1", gomock.Any()).Return(stored, nil)
+
+	next := func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler must not be called on replay")
+	}
+
+	req := newIdempotentRequest("key1")
+	rr := httptest.NewRecorder()
+
+	handler.UseIdempotency(next)(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "done", rr.Body.String())
+	assert.Equal(t, "true", rr.Header().Get("Idempotent-Replayed"))
+}
+
+func TestUseIdempotency_Mismatch(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().BeginIdempotentRequest(gomock.Any(), "user1", "key1", gomock.Any()).Return(nil, customerror.ErrIdempotencyKeyMismatch)
+
+	next := func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler must not be called on key mismatch")
+	}
+
+	req := newIdempotentRequest("key1")
+	rr := httptest.NewRecorder()
+
+	handler.UseIdempotency(next)(rr, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestUseIdempotency_ServerErrorReleasesKey(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	mockService.EXPECT().BeginIdempotentRequest(gomock.Any(), "user1", "key1", gomock.Any()).Return(nil, nil)
+	mockService.EXPECT().ReleaseIdempotentRequest(gomock.Any(), "user1", "key1").Return(nil)
+
+	next := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	req := newIdempotentRequest("key1")
+	rr := httptest.NewRecorder()
+
+	handler.UseIdempotency(next)(rr, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+}
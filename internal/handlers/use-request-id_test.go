@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUseRequestID_KeepsCallerID(t *testing.T) {
+	handler := UseRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/balance", nil)
+	req.Header.Set(requestIDHeader, "req-42")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, "req-42", rr.Header().Get(requestIDHeader))
+}
+
+func TestUseRequestID_ReplacesInvalidID(t *testing.T) {
+	handler := UseRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/balance", nil)
+	req.Header.Set(requestIDHeader, "bad id\nwith newline")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	requestID := rr.Header().Get(requestIDHeader)
+	assert.NotEqual(t, "bad id\nwith newline", requestID)
+	assert.Regexp(t, requestIDPattern, requestID)
+}
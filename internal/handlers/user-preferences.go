@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/with0p/gophermart/internal/auth"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func (h *HandlerUserAPI) GetUserPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	login, errLogin := auth.GetLoginFromRequestContext(ctx)
+	if errLogin != nil {
+		http.Error(w, errLogin.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	preferences, err := h.service.GetUserPreferences(ctx, login)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, preferences)
+}
+
+func (h *HandlerUserAPI) SetUserPreferences(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("content-type") != "application/json" {
+		http.Error(w, "Not a \"application/json\" content-type", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	login, errLogin := auth.GetLoginFromRequestContext(ctx)
+	if errLogin != nil {
+		http.Error(w, errLogin.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var preferences models.UserPreferences
+	if err := json.NewDecoder(r.Body).Decode(&preferences); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err := h.service.SetUserPreferences(ctx, login, preferences)
+	if err != nil {
+		if errors.Is(err, customerror.ErrUnknownTimeZone) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, preferences)
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/with0p/gophermart/internal/auth"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestSetUserPreferences_Success(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/user/preferences", strings.NewReader(`{"time_zone":"Europe/Moscow"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), auth.LoginKey, "user1"))
+	rr := httptest.NewRecorder()
+
+	mockService.EXPECT().SetUserPreferences(gomock.Any(), "user1", models.UserPreferences{TimeZone: "Europe/Moscow"}).Return(nil)
+
+	handler.SetUserPreferences(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"time_zone":"Europe/Moscow"}`, rr.Body.String())
+}
+
+func TestSetUserPreferences_UnknownTimeZone(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodPut, "/api/user/preferences", strings.NewReader(`{"time_zone":"Nowhere"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), auth.LoginKey, "user1"))
+	rr := httptest.NewRecorder()
+
+	mockService.EXPECT().SetUserPreferences(gomock.Any(), "user1", gomock.Any()).Return(customerror.ErrUnknownTimeZone)
+
+	handler.SetUserPreferences(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestGetUserOrders_UnknownTimeZone(t *testing.T) {
+	ctrl, _, handler := setup(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user/orders?tz=Nowhere", nil)
+	req = req.WithContext(context.WithValue(req.Context(), auth.LoginKey, "user1"))
+	rr := httptest.NewRecorder()
+
+	handler.GetUserOrders(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/with0p/gophermart/internal/auth"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/logger"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+// webhookService is the part of the service both the user and the admin
+// webhook routes are served from.
+type webhookService interface {
+	CreateWebhook(ctx context.Context, login string, endpointURL string, eventTypes []models.WebhookEventType) (*models.WebhookEndpoint, error)
+	GetWebhooks(ctx context.Context, login string) ([]models.WebhookEndpoint, error)
+	DeleteWebhook(ctx context.Context, login string, endpointID uuid.UUID) error
+	GetWebhookDeliveries(ctx context.Context, login string, endpointID uuid.UUID) ([]models.WebhookDelivery, error)
+	ReplayWebhookDelivery(ctx context.Context, login string, deliveryID int64) error
+}
+
+type CreateWebhookData struct {
+	URL        string                    `json:"url"`
+	EventTypes []models.WebhookEventType `json:"event_types"`
+}
+
+func (h *HandlerUserAPI) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	if login, ok := loginFromContext(w, r); ok {
+		serveCreateWebhook(w, r, h.service, login)
+	}
+}
+
+func (h *HandlerUserAPI) GetWebhooks(w http.ResponseWriter, r *http.Request) {
+	if login, ok := loginFromContext(w, r); ok {
+		serveGetWebhooks(w, r, h.service, login)
+	}
+}
+
+func (h *HandlerUserAPI) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if login, ok := loginFromContext(w, r); ok {
+		serveDeleteWebhook(w, r, h.service, login)
+	}
+}
+
+func (h *HandlerUserAPI) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	if login, ok := loginFromContext(w, r); ok {
+		serveGetWebhookDeliveries(w, r, h.service, login)
+	}
+}
+
+func (h *HandlerUserAPI) ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	if login, ok := loginFromContext(w, r); ok {
+		serveReplayWebhookDelivery(w, r, h.service, login)
+	}
+}
+
+func loginFromContext(w http.ResponseWriter, r *http.Request) (string, bool) {
+	login, err := auth.GetLoginFromRequestContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return "", false
+	}
+	return login, true
+}
+
+func serveCreateWebhook(w http.ResponseWriter, r *http.Request, svc webhookService, login string) {
+	if r.Header.Get("content-type") != "application/json" {
+		http.Error(w, "Not a \"application/json\" content-type", http.StatusBadRequest)
+		return
+	}
+
+	var data CreateWebhookData
+	if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	endpoint, err := svc.CreateWebhook(r.Context(), login, data.URL, data.EventTypes)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	// the secret is only ever shown here
+	writeJSON(w, http.StatusCreated, endpoint)
+}
+
+func serveGetWebhooks(w http.ResponseWriter, r *http.Request, svc webhookService, login string) {
+	endpoints, err := svc.GetWebhooks(r.Context(), login)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, endpoints)
+}
+
+func serveDeleteWebhook(w http.ResponseWriter, r *http.Request, svc webhookService, login string) {
+	endpointID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, customerror.ErrNoSuchWebhook.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err := svc.DeleteWebhook(r.Context(), login, endpointID); err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func serveGetWebhookDeliveries(w http.ResponseWriter, r *http.Request, svc webhookService, login string) {
+	endpointID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, customerror.ErrNoSuchWebhook.Error(), http.StatusNotFound)
+		return
+	}
+
+	deliveries, err := svc.GetWebhookDeliveries(r.Context(), login, endpointID)
+	if err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, deliveries)
+}
+
+func serveReplayWebhookDelivery(w http.ResponseWriter, r *http.Request, svc webhookService, login string) {
+	deliveryID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, customerror.ErrNoSuchWebhookDelivery.Error(), http.StatusNotFound)
+		return
+	}
+
+	if err := svc.ReplayWebhookDelivery(r.Context(), login, deliveryID); err != nil {
+		writeWebhookError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func writeWebhookError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, customerror.ErrInvalidWebhookURL), errors.Is(err, customerror.ErrForbiddenWebhookTarget),
+		errors.Is(err, customerror.ErrUnknownWebhookEvent):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, customerror.ErrNoSuchWebhook), errors.Is(err, customerror.ErrNoSuchWebhookDelivery), errors.Is(err, customerror.ErrNoSuchUser):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, customerror.ErrWebhookDeliveryPending):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		logger.Error(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/with0p/gophermart/internal/auth"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func withURLParam(req *http.Request, key string, value string) *http.Request {
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add(key, value)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx)
+	return req.WithContext(context.WithValue(ctx, auth.LoginKey, "user1"))
+}
+
+func TestCreateWebhook_Success(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/webhooks", strings.NewReader(`{"url":"https://shop.example/hook","event_types":["order.processed"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), auth.LoginKey, "user1"))
+	rr := httptest.NewRecorder()
+
+	mockService.EXPECT().CreateWebhook(gomock.Any(), "user1", "https://shop.example/hook", []models.WebhookEventType{models.WebhookOrderProcessed}).
+		Return(&models.WebhookEndpoint{ID: uuid.New(), URL: "https://shop.example/hook", Secret: "whsec_1"}, nil)
+
+	handler.CreateWebhook(rr, req)
+
+	assert.Equal(t, http.StatusCreated, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"secret":"whsec_1"`)
+}
+
+func TestCreateWebhook_InvalidURL(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/webhooks", strings.NewReader(`{"url":"localhost"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), auth.LoginKey, "user1"))
+	rr := httptest.NewRecorder()
+
+	mockService.EXPECT().CreateWebhook(gomock.Any(), "user1", "localhost", gomock.Any()).Return(nil, customerror.ErrInvalidWebhookURL)
+
+	handler.CreateWebhook(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestReplayWebhookDelivery_Success(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/user/webhooks/deliveries/7/replay", nil), "id", "7")
+	rr := httptest.NewRecorder()
+
+	mockService.EXPECT().ReplayWebhookDelivery(gomock.Any(), "user1", int64(7)).Return(nil)
+
+	handler.ReplayWebhookDelivery(rr, req)
+
+	assert.Equal(t, http.StatusAccepted, rr.Code)
+}
+
+func TestReplayWebhookDelivery_NotFound(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/user/webhooks/deliveries/7/replay", nil), "id", "7")
+	rr := httptest.NewRecorder()
+
+	mockService.EXPECT().ReplayWebhookDelivery(gomock.Any(), "user1", int64(7)).Return(customerror.ErrNoSuchWebhookDelivery)
+
+	handler.ReplayWebhookDelivery(rr, req)
+
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestReplayWebhookDelivery_Pending(t *testing.T) {
+	ctrl, mockService, handler := setup(t)
+	defer ctrl.Finish()
+
+	req := withURLParam(httptest.NewRequest(http.MethodPost, "/api/user/webhooks/deliveries/7/replay", nil), "id", "7")
+	rr := httptest.NewRecorder()
+
+	mockService.EXPECT().ReplayWebhookDelivery(gomock.Any(), "user1", int64(7)).Return(customerror.ErrWebhookDeliveryPending)
+
+	handler.ReplayWebhookDelivery(rr, req)
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+}
@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/with0p/gophermart/internal/auth"
+	"github.com/with0p/gophermart/internal/logger"
+)
+
+func (h *HandlerUserAPI) writeTokens(w http.ResponseWriter, r *http.Request, login string) {
+	tokens, err := h.authenticator.SetAuth(r.Context(), w, login)
+	if err != nil {
+		logger.ErrorContext(r.Context(), err)
+		http.Error(w, "Failed to generate token", http.StatusInternalServerError)
+		return
+	}
+
+	writeTokenPair(w, tokens)
+}
+
+func writeTokenPair(w http.ResponseWriter, tokens *auth.TokenPair) {
+	response, err := json.Marshal(tokens)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(response)
+}
@@ -1,17 +1,126 @@
 package logger
 
-import "go.uber.org/zap"
+import (
+	"context"
+	"fmt"
 
-var logger = zap.Must(zap.NewProduction()).Sugar()
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
 
-func Info(text string) {
-	logger.Infoln(
-		"info", text,
-	)
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+type Config struct {
+	// Level is one of debug, info, warn or error; info by default.
+	Level string
+	// Format is FormatJSON (the default) or FormatConsole.
+	Format string
+}
+
+var logger = zap.Must(build(Config{})).Sugar()
+
+// Setup replaces the default JSON info logger. It is meant to be called once
+// at startup, before any other goroutine logs.
+func Setup(conf Config) error {
+	built, err := build(conf)
+	if err != nil {
+		return err
+	}
+	logger = built.Sugar()
+	return nil
+}
+
+func build(conf Config) (*zap.Logger, error) {
+	level := zapcore.InfoLevel
+	if conf.Level != "" {
+		if err := level.UnmarshalText([]byte(conf.Level)); err != nil {
+			return nil, fmt.Errorf("%w: %q", customerror.ErrUnknownLogLevel, conf.Level)
+		}
+	}
+
+	var zapConfig zap.Config
+	switch conf.Format {
+	case "", FormatJSON:
+		zapConfig = zap.NewProductionConfig()
+	case FormatConsole:
+		zapConfig = zap.NewDevelopmentConfig()
+		zapConfig.Development = false
+		zapConfig.DisableStacktrace = true
+	default:
+		return nil, fmt.Errorf("%w: %q", customerror.ErrUnknownLogFormat, conf.Format)
+	}
+	zapConfig.Level = zap.NewAtomicLevelAt(level)
+
+	return zapConfig.Build(zap.AddCallerSkip(1))
+}
+
+type ctxFieldsKey struct{}
+
+// With returns a copy of ctx whose log entries carry the given key-value
+// pairs in addition to those already attached to ctx.
+func With(ctx context.Context, keysAndValues ...interface{}) context.Context {
+	parent := contextFields(ctx)
+	fields := make([]interface{}, 0, len(parent)+len(keysAndValues))
+	fields = append(fields, parent...)
+	fields = append(fields, keysAndValues...)
+	return context.WithValue(ctx, ctxFieldsKey{}, fields)
+}
+
+func contextFields(ctx context.Context) []interface{} {
+	fields, _ := ctx.Value(ctxFieldsKey{}).([]interface{})
+	return fields
+}
+
+func fromContext(ctx context.Context) *zap.SugaredLogger {
+	fields := append(contextFields(ctx), traceFields(ctx)...)
+	if len(fields) == 0 {
+		return logger
+	}
+	return logger.With(fields...)
+}
+
+func Info(text string, keysAndValues ...interface{}) {
+	logger.Infow(text, keysAndValues...)
+}
+
+func Warn(text string, keysAndValues ...interface{}) {
+	logger.Warnw(text, keysAndValues...)
+}
+
+func Error(err error, keysAndValues ...interface{}) {
+	logger.Errorw(err.Error(), keysAndValues...)
+}
+
+// DebugContext logs with the fields attached to ctx and its trace and span
+// IDs; so do the other *Context functions.
+func DebugContext(ctx context.Context, text string, keysAndValues ...interface{}) {
+	fromContext(ctx).Debugw(text, keysAndValues...)
+}
+
+func InfoContext(ctx context.Context, text string, keysAndValues ...interface{}) {
+	fromContext(ctx).Infow(text, keysAndValues...)
+}
+
+func WarnContext(ctx context.Context, text string, keysAndValues ...interface{}) {
+	fromContext(ctx).Warnw(text, keysAndValues...)
+}
+
+func ErrorContext(ctx context.Context, err error, keysAndValues ...interface{}) {
+	fromContext(ctx).Errorw(err.Error(), keysAndValues...)
 }
 
-func Error(err error) {
-	logger.Errorln(
-		"ERROR", err.Error(),
-	)
+func traceFields(ctx context.Context) []interface{} {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return nil
+	}
+	return []interface{}{
+		"trace_id", spanCtx.TraceID().String(),
+		"span_id", spanCtx.SpanID().String(),
+	}
 }
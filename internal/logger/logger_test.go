@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func observe(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.DebugLevel)
+	previous := logger
+	logger = zap.New(core).Sugar()
+	t.Cleanup(func() { logger = previous })
+	return logs
+}
+
+func TestWith_FieldsAccumulate(t *testing.T) {
+	logs := observe(t)
+
+	ctx := With(context.Background(), "request_id", "abc")
+	child := With(ctx, "login", "user1")
+	sibling := With(ctx, "worker", 2)
+
+	ErrorContext(child, errors.New("boom"), "order_id", "12345678903")
+	InfoContext(sibling, "started")
+
+	entries := logs.All()
+	assert.Equal(t, "boom", entries[0].Message)
+	assert.Equal(t, map[string]interface{}{"request_id": "abc", "login": "user1", "order_id": "12345678903"}, entries[0].ContextMap())
+	assert.Equal(t, map[string]interface{}{"request_id": "abc", "worker": int64(2)}, entries[1].ContextMap())
+}
+
+func TestSetup_RejectsUnknownLevelAndFormat(t *testing.T) {
+	assert.ErrorIs(t, Setup(Config{Level: "loud"}), customerror.ErrUnknownLogLevel)
+	assert.ErrorIs(t, Setup(Config{Format: "xml"}), customerror.ErrUnknownLogFormat)
+}
+
+func TestSetup_Level(t *testing.T) {
+	previous := logger
+	t.Cleanup(func() { logger = previous })
+
+	assert.NoError(t, Setup(Config{Level: "warn", Format: FormatConsole}))
+	assert.False(t, logger.Desugar().Core().Enabled(zapcore.InfoLevel))
+	assert.True(t, logger.Desugar().Core().Enabled(zapcore.WarnLevel))
+}
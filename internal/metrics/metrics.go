@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/with0p/gophermart/internal/logger"
+)
+
+const namespace = "gophermart"
+
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by route pattern, method and status code.",
+	}, []string{"route", "method", "code"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by route pattern and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	AccrualRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "accrual_requests_total",
+		Help:      "Requests to the accrual system by outcome: 200, 204, 429, 5xx, other or error.",
+	}, []string{"outcome"})
+
+	OrderStateDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "order_state_duration_seconds",
+		Help:      "Time orders spent in a status before leaving it.",
+		Buckets:   []float64{1, 5, 15, 60, 300, 900, 3600, 4 * 3600, 24 * 3600},
+	}, []string{"status"})
+
+	PointsAccrued = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "points_accrued_total",
+		Help:      "Points credited to users for processed orders.",
+	})
+
+	PointsWithdrawn = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "points_withdrawn_total",
+		Help:      "Points withdrawn by users.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		AccrualRequests,
+		OrderStateDuration,
+		PointsAccrued,
+		PointsWithdrawn,
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDB exposes the connection pool statistics of db on reg, which is
+// Registry for the server.
+func RegisterDB(reg prometheus.Registerer, db *sql.DB) error {
+	return reg.Register(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// RegisterQueueDepth exposes the accrual job queue size on reg, read with
+// count on every scrape.
+func RegisterQueueDepth(reg prometheus.Registerer, count func(ctx context.Context) (int, error)) error {
+	return reg.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "accrual_queue_depth",
+		Help:      "Orders waiting to be checked against the accrual system.",
+	}, func() float64 {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		depth, err := count(ctx)
+		if err != nil {
+			logger.Error(err)
+			return -1
+		}
+		return float64(depth)
+	}))
+}
@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterQueueDepth(t *testing.T) {
+	count := func(context.Context) (int, error) { return 7, nil }
+
+	// every server gets its own registry, so building two is fine
+	for i := 0; i < 2; i++ {
+		reg := prometheus.NewRegistry()
+		require.NoError(t, RegisterQueueDepth(reg, count))
+
+		n, err := testutil.GatherAndCount(reg, "gophermart_accrual_queue_depth")
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		// registering twice on one registry is an error, not a panic
+		assert.Error(t, RegisterQueueDepth(reg, count))
+	}
+}
@@ -0,0 +1,24 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/with0p/gophermart/internal/routing"
+)
+
+// Middleware records requests under their chi route pattern rather than the
+// raw path, so order numbers and logins do not become label values.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := routing.WrapWriter(w, r)
+
+		next.ServeHTTP(ww, r)
+
+		route, _ := routing.Pattern(r)
+		HTTPRequests.WithLabelValues(route, r.Method, strconv.Itoa(routing.Status(ww))).Inc()
+		HTTPDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+	})
+}
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_LabelsByRoutePattern(t *testing.T) {
+	mux := chi.NewRouter()
+	mux.Use(Middleware)
+	mux.Get(`/api/user/orders/{id}`, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	before := testutil.ToFloat64(HTTPRequests.WithLabelValues("/api/user/orders/{id}", http.MethodGet, "202"))
+	for _, id := range []string{"12345678903", "2377225624"} {
+		mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/user/orders/"+id, nil))
+	}
+
+	after := testutil.ToFloat64(HTTPRequests.WithLabelValues("/api/user/orders/{id}", http.MethodGet, "202"))
+	assert.Equal(t, float64(2), after-before)
+}
+
+func TestMiddleware_Unmatched(t *testing.T) {
+	mux := chi.NewRouter()
+	mux.Use(Middleware)
+	mux.Get(`/known`, func(w http.ResponseWriter, r *http.Request) {})
+
+	before := testutil.ToFloat64(HTTPRequests.WithLabelValues("unmatched", http.MethodGet, "404"))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown/12345678903", nil))
+
+	after := testutil.ToFloat64(HTTPRequests.WithLabelValues("unmatched", http.MethodGet, "404"))
+	assert.Equal(t, float64(1), after-before)
+}
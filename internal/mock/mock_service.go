@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
 	models "github.com/with0p/gophermart/internal/models"
 )
 
@@ -49,6 +50,21 @@ func (mr *MockServiceMockRecorder) AddOrder(arg0, arg1, arg2 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrder", reflect.TypeOf((*MockService)(nil).AddOrder), arg0, arg1, arg2)
 }
 
+// AddOrders mocks base method.
+func (m *MockService) AddOrders(arg0 context.Context, arg1 string, arg2 []models.OrderID) ([]models.OrderUploadResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrders", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.OrderUploadResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOrders indicates an expected call of AddOrders.
+func (mr *MockServiceMockRecorder) AddOrders(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrders", reflect.TypeOf((*MockService)(nil).AddOrders), arg0, arg1, arg2)
+}
+
 // AuthenticateUser mocks base method.
 func (m *MockService) AuthenticateUser(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -63,16 +79,76 @@ func (mr *MockServiceMockRecorder) AuthenticateUser(arg0, arg1, arg2 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateUser", reflect.TypeOf((*MockService)(nil).AuthenticateUser), arg0, arg1, arg2)
 }
 
-// FeedQueue mocks base method.
-func (m *MockService) FeedQueue(arg0 chan models.OrderID) {
+// BeginIdempotentRequest mocks base method.
+func (m *MockService) BeginIdempotentRequest(arg0 context.Context, arg1, arg2, arg3 string) (*models.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginIdempotentRequest", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.IdempotentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginIdempotentRequest indicates an expected call of BeginIdempotentRequest.
+func (mr *MockServiceMockRecorder) BeginIdempotentRequest(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginIdempotentRequest", reflect.TypeOf((*MockService)(nil).BeginIdempotentRequest), arg0, arg1, arg2, arg3)
+}
+
+// CheckReadiness mocks base method.
+func (m *MockService) CheckReadiness(arg0 context.Context) models.Readiness {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckReadiness", arg0)
+	ret0, _ := ret[0].(models.Readiness)
+	return ret0
+}
+
+// CheckReadiness indicates an expected call of CheckReadiness.
+func (mr *MockServiceMockRecorder) CheckReadiness(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckReadiness", reflect.TypeOf((*MockService)(nil).CheckReadiness), arg0)
+}
+
+// CompleteIdempotentRequest mocks base method.
+func (m *MockService) CompleteIdempotentRequest(arg0 context.Context, arg1, arg2 string, arg3 models.IdempotentResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotentRequest", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotentRequest indicates an expected call of CompleteIdempotentRequest.
+func (mr *MockServiceMockRecorder) CompleteIdempotentRequest(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotentRequest", reflect.TypeOf((*MockService)(nil).CompleteIdempotentRequest), arg0, arg1, arg2, arg3)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockService) CreateWebhook(arg0 context.Context, arg1, arg2 string, arg3 []models.WebhookEventType) (*models.WebhookEndpoint, error) {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "FeedQueue", arg0)
+	ret := m.ctrl.Call(m, "CreateWebhook", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// FeedQueue indicates an expected call of FeedQueue.
-func (mr *MockServiceMockRecorder) FeedQueue(arg0 interface{}) *gomock.Call {
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockServiceMockRecorder) CreateWebhook(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FeedQueue", reflect.TypeOf((*MockService)(nil).FeedQueue), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockService)(nil).CreateWebhook), arg0, arg1, arg2, arg3)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockService) DeleteWebhook(arg0 context.Context, arg1 string, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockServiceMockRecorder) DeleteWebhook(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockService)(nil).DeleteWebhook), arg0, arg1, arg2)
 }
 
 // GetUserBalance mocks base method.
@@ -91,37 +167,84 @@ func (mr *MockServiceMockRecorder) GetUserBalance(arg0, arg1 interface{}) *gomoc
 }
 
 // GetUserOrders mocks base method.
-func (m *MockService) GetUserOrders(arg0 context.Context, arg1 string) ([]models.Order, error) {
+func (m *MockService) GetUserOrders(arg0 context.Context, arg1 string, arg2 models.ListQuery) ([]models.Order, *models.Cursor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserOrders", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetUserOrders", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(*models.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserOrders indicates an expected call of GetUserOrders.
+func (mr *MockServiceMockRecorder) GetUserOrders(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOrders", reflect.TypeOf((*MockService)(nil).GetUserOrders), arg0, arg1, arg2)
+}
+
+// GetUserPreferences mocks base method.
+func (m *MockService) GetUserPreferences(arg0 context.Context, arg1 string) (*models.UserPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPreferences", arg0, arg1)
+	ret0, _ := ret[0].(*models.UserPreferences)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserOrders indicates an expected call of GetUserOrders.
-func (mr *MockServiceMockRecorder) GetUserOrders(arg0, arg1 interface{}) *gomock.Call {
+// GetUserPreferences indicates an expected call of GetUserPreferences.
+func (mr *MockServiceMockRecorder) GetUserPreferences(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOrders", reflect.TypeOf((*MockService)(nil).GetUserOrders), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPreferences", reflect.TypeOf((*MockService)(nil).GetUserPreferences), arg0, arg1)
 }
 
 // GetUserWithdrawals mocks base method.
-func (m *MockService) GetUserWithdrawals(arg0 context.Context, arg1 string) ([]models.Withdrawal, error) {
+func (m *MockService) GetUserWithdrawals(arg0 context.Context, arg1 string, arg2 models.ListQuery) ([]models.Withdrawal, *models.Cursor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserWithdrawals", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetUserWithdrawals", arg0, arg1, arg2)
 	ret0, _ := ret[0].([]models.Withdrawal)
+	ret1, _ := ret[1].(*models.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserWithdrawals indicates an expected call of GetUserWithdrawals.
+func (mr *MockServiceMockRecorder) GetUserWithdrawals(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWithdrawals", reflect.TypeOf((*MockService)(nil).GetUserWithdrawals), arg0, arg1, arg2)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockService) GetWebhookDeliveries(arg0 context.Context, arg1 string, arg2 uuid.UUID) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserWithdrawals indicates an expected call of GetUserWithdrawals.
-func (mr *MockServiceMockRecorder) GetUserWithdrawals(arg0, arg1 interface{}) *gomock.Call {
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockServiceMockRecorder) GetWebhookDeliveries(arg0, arg1, arg2 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWithdrawals", reflect.TypeOf((*MockService)(nil).GetUserWithdrawals), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockService)(nil).GetWebhookDeliveries), arg0, arg1, arg2)
+}
+
+// GetWebhooks mocks base method.
+func (m *MockService) GetWebhooks(arg0 context.Context, arg1 string) ([]models.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooks", arg0, arg1)
+	ret0, _ := ret[0].([]models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooks indicates an expected call of GetWebhooks.
+func (mr *MockServiceMockRecorder) GetWebhooks(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooks", reflect.TypeOf((*MockService)(nil).GetWebhooks), arg0, arg1)
 }
 
 // MakeWithdrawal mocks base method.
-func (m *MockService) MakeWithdrawal(arg0 context.Context, arg1 string, arg2 models.OrderID, arg3 float32) error {
+func (m *MockService) MakeWithdrawal(arg0 context.Context, arg1 string, arg2 models.OrderID, arg3 models.Amount) error {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "MakeWithdrawal", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
@@ -134,18 +257,6 @@ func (mr *MockServiceMockRecorder) MakeWithdrawal(arg0, arg1, arg2, arg3 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MakeWithdrawal", reflect.TypeOf((*MockService)(nil).MakeWithdrawal), arg0, arg1, arg2, arg3)
 }
 
-// ProcessOrders mocks base method.
-func (m *MockService) ProcessOrders(arg0 chan models.OrderID, arg1 string) {
-	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "ProcessOrders", arg0, arg1)
-}
-
-// ProcessOrders indicates an expected call of ProcessOrders.
-func (mr *MockServiceMockRecorder) ProcessOrders(arg0, arg1 interface{}) *gomock.Call {
-	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessOrders", reflect.TypeOf((*MockService)(nil).ProcessOrders), arg0, arg1)
-}
-
 // RegisterUser mocks base method.
 func (m *MockService) RegisterUser(arg0 context.Context, arg1, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -159,3 +270,88 @@ func (mr *MockServiceMockRecorder) RegisterUser(arg0, arg1, arg2 interface{}) *g
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterUser", reflect.TypeOf((*MockService)(nil).RegisterUser), arg0, arg1, arg2)
 }
+
+// ReleaseIdempotentRequest mocks base method.
+func (m *MockService) ReleaseIdempotentRequest(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotentRequest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotentRequest indicates an expected call of ReleaseIdempotentRequest.
+func (mr *MockServiceMockRecorder) ReleaseIdempotentRequest(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotentRequest", reflect.TypeOf((*MockService)(nil).ReleaseIdempotentRequest), arg0, arg1, arg2)
+}
+
+// ReplayWebhookDelivery mocks base method.
+func (m *MockService) ReplayWebhookDelivery(arg0 context.Context, arg1 string, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayWebhookDelivery", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplayWebhookDelivery indicates an expected call of ReplayWebhookDelivery.
+func (mr *MockServiceMockRecorder) ReplayWebhookDelivery(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayWebhookDelivery", reflect.TypeOf((*MockService)(nil).ReplayWebhookDelivery), arg0, arg1, arg2)
+}
+
+// SetUserPreferences mocks base method.
+func (m *MockService) SetUserPreferences(arg0 context.Context, arg1 string, arg2 models.UserPreferences) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserPreferences", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserPreferences indicates an expected call of SetUserPreferences.
+func (mr *MockServiceMockRecorder) SetUserPreferences(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserPreferences", reflect.TypeOf((*MockService)(nil).SetUserPreferences), arg0, arg1, arg2)
+}
+
+// Start mocks base method.
+func (m *MockService) Start(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockServiceMockRecorder) Start(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockService)(nil).Start), arg0)
+}
+
+// Stop mocks base method.
+func (m *MockService) Stop(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Stop", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockServiceMockRecorder) Stop(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockService)(nil).Stop), arg0)
+}
+
+// SubscribeOrderEvents mocks base method.
+func (m *MockService) SubscribeOrderEvents(arg0 context.Context, arg1 string, arg2 int64) (<-chan models.OrderEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeOrderEvents", arg0, arg1, arg2)
+	ret0, _ := ret[0].(<-chan models.OrderEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeOrderEvents indicates an expected call of SubscribeOrderEvents.
+func (mr *MockServiceMockRecorder) SubscribeOrderEvents(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeOrderEvents", reflect.TypeOf((*MockService)(nil).SubscribeOrderEvents), arg0, arg1, arg2)
+}
@@ -0,0 +1,619 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: github.com/with0p/gophermart/internal/storage (interfaces: Storage)
+
+// Package mock is a generated GoMock package.
+package mock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	uuid "github.com/google/uuid"
+	models "github.com/with0p/gophermart/internal/models"
+)
+
+// MockStorage is a mock of Storage interface.
+type MockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockStorageMockRecorder
+}
+
+// MockStorageMockRecorder is the mock recorder for MockStorage.
+type MockStorageMockRecorder struct {
+	mock *MockStorage
+}
+
+// NewMockStorage creates a new mock instance.
+func NewMockStorage(ctrl *gomock.Controller) *MockStorage {
+	mock := &MockStorage{ctrl: ctrl}
+	mock.recorder = &MockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
+	return m.recorder
+}
+
+// AddOrder mocks base method.
+func (m *MockStorage) AddOrder(arg0 context.Context, arg1 uuid.UUID, arg2 models.OrderStatus, arg3 models.OrderID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrder", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOrder indicates an expected call of AddOrder.
+func (mr *MockStorageMockRecorder) AddOrder(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrder", reflect.TypeOf((*MockStorage)(nil).AddOrder), arg0, arg1, arg2, arg3)
+}
+
+// AddOrders mocks base method.
+func (m *MockStorage) AddOrders(arg0 context.Context, arg1 uuid.UUID, arg2 models.OrderStatus, arg3 []models.OrderID) ([]models.OrderUploadResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrders", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.OrderUploadResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddOrders indicates an expected call of AddOrders.
+func (mr *MockStorageMockRecorder) AddOrders(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrders", reflect.TypeOf((*MockStorage)(nil).AddOrders), arg0, arg1, arg2, arg3)
+}
+
+// AddWithdrawal mocks base method.
+func (m *MockStorage) AddWithdrawal(arg0 context.Context, arg1 uuid.UUID, arg2 models.OrderID, arg3 models.Amount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWithdrawal", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddWithdrawal indicates an expected call of AddWithdrawal.
+func (mr *MockStorageMockRecorder) AddWithdrawal(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWithdrawal", reflect.TypeOf((*MockStorage)(nil).AddWithdrawal), arg0, arg1, arg2, arg3)
+}
+
+// AdjustBalance mocks base method.
+func (m *MockStorage) AdjustBalance(arg0 context.Context, arg1 uuid.UUID, arg2 models.Amount, arg3, arg4 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBalance", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AdjustBalance indicates an expected call of AdjustBalance.
+func (mr *MockStorageMockRecorder) AdjustBalance(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBalance", reflect.TypeOf((*MockStorage)(nil).AdjustBalance), arg0, arg1, arg2, arg3, arg4)
+}
+
+// BeginIdempotentRequest mocks base method.
+func (m *MockStorage) BeginIdempotentRequest(arg0 context.Context, arg1 uuid.UUID, arg2, arg3 string) (*models.IdempotentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginIdempotentRequest", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*models.IdempotentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginIdempotentRequest indicates an expected call of BeginIdempotentRequest.
+func (mr *MockStorageMockRecorder) BeginIdempotentRequest(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginIdempotentRequest", reflect.TypeOf((*MockStorage)(nil).BeginIdempotentRequest), arg0, arg1, arg2, arg3)
+}
+
+// ClaimOrderJobs mocks base method.
+func (m *MockStorage) ClaimOrderJobs(arg0 context.Context, arg1 int, arg2 time.Duration) ([]models.OrderJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOrderJobs", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.OrderJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOrderJobs indicates an expected call of ClaimOrderJobs.
+func (mr *MockStorageMockRecorder) ClaimOrderJobs(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOrderJobs", reflect.TypeOf((*MockStorage)(nil).ClaimOrderJobs), arg0, arg1, arg2)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockStorage) ClaimWebhookDeliveries(arg0 context.Context, arg1 int, arg2 time.Duration) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockStorageMockRecorder) ClaimWebhookDeliveries(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockStorage)(nil).ClaimWebhookDeliveries), arg0, arg1, arg2)
+}
+
+// CompleteIdempotentRequest mocks base method.
+func (m *MockStorage) CompleteIdempotentRequest(arg0 context.Context, arg1 uuid.UUID, arg2 string, arg3 models.IdempotentResponse) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteIdempotentRequest", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteIdempotentRequest indicates an expected call of CompleteIdempotentRequest.
+func (mr *MockStorageMockRecorder) CompleteIdempotentRequest(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteIdempotentRequest", reflect.TypeOf((*MockStorage)(nil).CompleteIdempotentRequest), arg0, arg1, arg2, arg3)
+}
+
+// CountOrderJobs mocks base method.
+func (m *MockStorage) CountOrderJobs(arg0 context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOrderJobs", arg0)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOrderJobs indicates an expected call of CountOrderJobs.
+func (mr *MockStorageMockRecorder) CountOrderJobs(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOrderJobs", reflect.TypeOf((*MockStorage)(nil).CountOrderJobs), arg0)
+}
+
+// CreateUser mocks base method.
+func (m *MockStorage) CreateUser(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStorageMockRecorder) CreateUser(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStorage)(nil).CreateUser), arg0, arg1, arg2)
+}
+
+// CreateWebhookEndpoint mocks base method.
+func (m *MockStorage) CreateWebhookEndpoint(arg0 context.Context, arg1 uuid.UUID, arg2 models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookEndpoint", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookEndpoint indicates an expected call of CreateWebhookEndpoint.
+func (mr *MockStorageMockRecorder) CreateWebhookEndpoint(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookEndpoint", reflect.TypeOf((*MockStorage)(nil).CreateWebhookEndpoint), arg0, arg1, arg2)
+}
+
+// DeleteWebhookEndpoint mocks base method.
+func (m *MockStorage) DeleteWebhookEndpoint(arg0 context.Context, arg1, arg2 uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhookEndpoint", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhookEndpoint indicates an expected call of DeleteWebhookEndpoint.
+func (mr *MockStorageMockRecorder) DeleteWebhookEndpoint(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhookEndpoint", reflect.TypeOf((*MockStorage)(nil).DeleteWebhookEndpoint), arg0, arg1, arg2)
+}
+
+// FinishOrderJob mocks base method.
+func (m *MockStorage) FinishOrderJob(arg0 context.Context, arg1 models.OrderID, arg2 models.OrderStatus, arg3 models.Amount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishOrderJob", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishOrderJob indicates an expected call of FinishOrderJob.
+func (mr *MockStorageMockRecorder) FinishOrderJob(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishOrderJob", reflect.TypeOf((*MockStorage)(nil).FinishOrderJob), arg0, arg1, arg2, arg3)
+}
+
+// FinishWebhookDelivery mocks base method.
+func (m *MockStorage) FinishWebhookDelivery(arg0 context.Context, arg1 int64, arg2 models.WebhookDeliveryStatus, arg3 time.Time, arg4 int, arg5 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishWebhookDelivery", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishWebhookDelivery indicates an expected call of FinishWebhookDelivery.
+func (mr *MockStorageMockRecorder) FinishWebhookDelivery(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishWebhookDelivery", reflect.TypeOf((*MockStorage)(nil).FinishWebhookDelivery), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// GetOrder mocks base method.
+func (m *MockStorage) GetOrder(arg0 context.Context, arg1 models.OrderID) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", arg0, arg1)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockStorageMockRecorder) GetOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockStorage)(nil).GetOrder), arg0, arg1)
+}
+
+// GetOrderEvents mocks base method.
+func (m *MockStorage) GetOrderEvents(arg0 context.Context, arg1 uuid.UUID, arg2 int64, arg3 int) ([]models.OrderEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderEvents", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.OrderEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderEvents indicates an expected call of GetOrderEvents.
+func (mr *MockStorageMockRecorder) GetOrderEvents(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderEvents", reflect.TypeOf((*MockStorage)(nil).GetOrderEvents), arg0, arg1, arg2, arg3)
+}
+
+// GetUserBalance mocks base method.
+func (m *MockStorage) GetUserBalance(arg0 context.Context, arg1 uuid.UUID) (*models.Balance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserBalance", arg0, arg1)
+	ret0, _ := ret[0].(*models.Balance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserBalance indicates an expected call of GetUserBalance.
+func (mr *MockStorageMockRecorder) GetUserBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserBalance", reflect.TypeOf((*MockStorage)(nil).GetUserBalance), arg0, arg1)
+}
+
+// GetUserID mocks base method.
+func (m *MockStorage) GetUserID(arg0 context.Context, arg1 string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserID", arg0, arg1)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserID indicates an expected call of GetUserID.
+func (mr *MockStorageMockRecorder) GetUserID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserID", reflect.TypeOf((*MockStorage)(nil).GetUserID), arg0, arg1)
+}
+
+// GetUserInfo mocks base method.
+func (m *MockStorage) GetUserInfo(arg0 context.Context, arg1 string) (*models.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserInfo", arg0, arg1)
+	ret0, _ := ret[0].(*models.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserInfo indicates an expected call of GetUserInfo.
+func (mr *MockStorageMockRecorder) GetUserInfo(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserInfo", reflect.TypeOf((*MockStorage)(nil).GetUserInfo), arg0, arg1)
+}
+
+// GetUserLedger mocks base method.
+func (m *MockStorage) GetUserLedger(arg0 context.Context, arg1 uuid.UUID) ([]models.LedgerEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserLedger", arg0, arg1)
+	ret0, _ := ret[0].([]models.LedgerEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserLedger indicates an expected call of GetUserLedger.
+func (mr *MockStorageMockRecorder) GetUserLedger(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserLedger", reflect.TypeOf((*MockStorage)(nil).GetUserLedger), arg0, arg1)
+}
+
+// GetUserOrders mocks base method.
+func (m *MockStorage) GetUserOrders(arg0 context.Context, arg1 uuid.UUID, arg2 models.ListQuery) ([]models.Order, *models.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserOrders", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(*models.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserOrders indicates an expected call of GetUserOrders.
+func (mr *MockStorageMockRecorder) GetUserOrders(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserOrders", reflect.TypeOf((*MockStorage)(nil).GetUserOrders), arg0, arg1, arg2)
+}
+
+// GetUserPasswordHash mocks base method.
+func (m *MockStorage) GetUserPasswordHash(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPasswordHash", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPasswordHash indicates an expected call of GetUserPasswordHash.
+func (mr *MockStorageMockRecorder) GetUserPasswordHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPasswordHash", reflect.TypeOf((*MockStorage)(nil).GetUserPasswordHash), arg0, arg1)
+}
+
+// GetUserPreferences mocks base method.
+func (m *MockStorage) GetUserPreferences(arg0 context.Context, arg1 uuid.UUID) (*models.UserPreferences, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPreferences", arg0, arg1)
+	ret0, _ := ret[0].(*models.UserPreferences)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPreferences indicates an expected call of GetUserPreferences.
+func (mr *MockStorageMockRecorder) GetUserPreferences(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPreferences", reflect.TypeOf((*MockStorage)(nil).GetUserPreferences), arg0, arg1)
+}
+
+// GetUserWithdrawals mocks base method.
+func (m *MockStorage) GetUserWithdrawals(arg0 context.Context, arg1 uuid.UUID, arg2 models.ListQuery) ([]models.Withdrawal, *models.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserWithdrawals", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]models.Withdrawal)
+	ret1, _ := ret[1].(*models.Cursor)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUserWithdrawals indicates an expected call of GetUserWithdrawals.
+func (mr *MockStorageMockRecorder) GetUserWithdrawals(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserWithdrawals", reflect.TypeOf((*MockStorage)(nil).GetUserWithdrawals), arg0, arg1, arg2)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockStorage) GetWebhookDeliveries(arg0 context.Context, arg1, arg2 uuid.UUID, arg3 int) ([]models.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockStorageMockRecorder) GetWebhookDeliveries(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockStorage)(nil).GetWebhookDeliveries), arg0, arg1, arg2, arg3)
+}
+
+// GetWebhookEndpoints mocks base method.
+func (m *MockStorage) GetWebhookEndpoints(arg0 context.Context, arg1 uuid.UUID) ([]models.WebhookEndpoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookEndpoints", arg0, arg1)
+	ret0, _ := ret[0].([]models.WebhookEndpoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookEndpoints indicates an expected call of GetWebhookEndpoints.
+func (mr *MockStorageMockRecorder) GetWebhookEndpoints(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookEndpoints", reflect.TypeOf((*MockStorage)(nil).GetWebhookEndpoints), arg0, arg1)
+}
+
+// KillOrderJob mocks base method.
+func (m *MockStorage) KillOrderJob(arg0 context.Context, arg1 models.OrderID, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KillOrderJob", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// KillOrderJob indicates an expected call of KillOrderJob.
+func (mr *MockStorageMockRecorder) KillOrderJob(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KillOrderJob", reflect.TypeOf((*MockStorage)(nil).KillOrderJob), arg0, arg1, arg2)
+}
+
+// ListUsers mocks base method.
+func (m *MockStorage) ListUsers(arg0 context.Context, arg1 string, arg2, arg3 int) ([]models.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]models.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockStorageMockRecorder) ListUsers(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockStorage)(nil).ListUsers), arg0, arg1, arg2, arg3)
+}
+
+// ListenOrderEvents mocks base method.
+func (m *MockStorage) ListenOrderEvents(arg0 context.Context, arg1 func(models.OrderEvent)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListenOrderEvents", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListenOrderEvents indicates an expected call of ListenOrderEvents.
+func (mr *MockStorageMockRecorder) ListenOrderEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListenOrderEvents", reflect.TypeOf((*MockStorage)(nil).ListenOrderEvents), arg0, arg1)
+}
+
+// Ping mocks base method.
+func (m *MockStorage) Ping(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ping", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Ping indicates an expected call of Ping.
+func (mr *MockStorageMockRecorder) Ping(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ping", reflect.TypeOf((*MockStorage)(nil).Ping), arg0)
+}
+
+// PruneOrderEvents mocks base method.
+func (m *MockStorage) PruneOrderEvents(arg0 context.Context, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneOrderEvents", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PruneOrderEvents indicates an expected call of PruneOrderEvents.
+func (mr *MockStorageMockRecorder) PruneOrderEvents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneOrderEvents", reflect.TypeOf((*MockStorage)(nil).PruneOrderEvents), arg0, arg1)
+}
+
+// ReleaseIdempotentRequest mocks base method.
+func (m *MockStorage) ReleaseIdempotentRequest(arg0 context.Context, arg1 uuid.UUID, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseIdempotentRequest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseIdempotentRequest indicates an expected call of ReleaseIdempotentRequest.
+func (mr *MockStorageMockRecorder) ReleaseIdempotentRequest(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseIdempotentRequest", reflect.TypeOf((*MockStorage)(nil).ReleaseIdempotentRequest), arg0, arg1, arg2)
+}
+
+// ReplayWebhookDelivery mocks base method.
+func (m *MockStorage) ReplayWebhookDelivery(arg0 context.Context, arg1 uuid.UUID, arg2 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayWebhookDelivery", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplayWebhookDelivery indicates an expected call of ReplayWebhookDelivery.
+func (mr *MockStorageMockRecorder) ReplayWebhookDelivery(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayWebhookDelivery", reflect.TypeOf((*MockStorage)(nil).ReplayWebhookDelivery), arg0, arg1, arg2)
+}
+
+// RequeueOrderJob mocks base method.
+func (m *MockStorage) RequeueOrderJob(arg0 context.Context, arg1 models.OrderID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueOrderJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequeueOrderJob indicates an expected call of RequeueOrderJob.
+func (mr *MockStorageMockRecorder) RequeueOrderJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueOrderJob", reflect.TypeOf((*MockStorage)(nil).RequeueOrderJob), arg0, arg1)
+}
+
+// RescheduleOrderJob mocks base method.
+func (m *MockStorage) RescheduleOrderJob(arg0 context.Context, arg1 models.OrderID, arg2 time.Time, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescheduleOrderJob", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescheduleOrderJob indicates an expected call of RescheduleOrderJob.
+func (mr *MockStorageMockRecorder) RescheduleOrderJob(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescheduleOrderJob", reflect.TypeOf((*MockStorage)(nil).RescheduleOrderJob), arg0, arg1, arg2, arg3)
+}
+
+// SetUserLocked mocks base method.
+func (m *MockStorage) SetUserLocked(arg0 context.Context, arg1 string, arg2 bool, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserLocked", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserLocked indicates an expected call of SetUserLocked.
+func (mr *MockStorageMockRecorder) SetUserLocked(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserLocked", reflect.TypeOf((*MockStorage)(nil).SetUserLocked), arg0, arg1, arg2, arg3)
+}
+
+// SetUserPreferences mocks base method.
+func (m *MockStorage) SetUserPreferences(arg0 context.Context, arg1 uuid.UUID, arg2 models.UserPreferences) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserPreferences", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserPreferences indicates an expected call of SetUserPreferences.
+func (mr *MockStorageMockRecorder) SetUserPreferences(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserPreferences", reflect.TypeOf((*MockStorage)(nil).SetUserPreferences), arg0, arg1, arg2)
+}
+
+// SetUserRoles mocks base method.
+func (m *MockStorage) SetUserRoles(arg0 context.Context, arg1 string, arg2 []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRoles", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserRoles indicates an expected call of SetUserRoles.
+func (mr *MockStorageMockRecorder) SetUserRoles(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRoles", reflect.TypeOf((*MockStorage)(nil).SetUserRoles), arg0, arg1, arg2)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockStorage) UpdateOrderStatus(arg0 context.Context, arg1 models.OrderID, arg2 models.OrderStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockStorageMockRecorder) UpdateOrderStatus(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockStorage)(nil).UpdateOrderStatus), arg0, arg1, arg2)
+}
+
+// UpdateUserPasswordHash mocks base method.
+func (m *MockStorage) UpdateUserPasswordHash(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPasswordHash", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserPasswordHash indicates an expected call of UpdateUserPasswordHash.
+func (mr *MockStorageMockRecorder) UpdateUserPasswordHash(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordHash", reflect.TypeOf((*MockStorage)(nil).UpdateUserPasswordHash), arg0, arg1, arg2, arg3)
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Amount is a number of loyalty points held in hundredths, so 729.98 points
+// is stored as 72998. It is rendered as a plain JSON number.
+type Amount int64
+
+const amountScale = 100
+
+// decimalPattern is the JSON number grammar. Anything else, such as the
+// fractions big.Rat would accept, is not an amount.
+var decimalPattern = regexp.MustCompile(`^(-?)(0|[1-9][0-9]*)(?:\.([0-9]+))?(?:[eE]([+-]?[0-9]+))?$`)
+
+// maxAmountDigits bounds the exponent: an int64 has at most 19 digits.
+const maxAmountDigits = 19
+
+// ParseAmount reads a decimal number of points, rounding half away from zero
+// to hundredths.
+func ParseAmount(value string) (Amount, error) {
+	match := decimalPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+	sign, whole, fraction := match[1], match[2], match[3]
+
+	exp := 0
+	if match[4] != "" {
+		var err error
+		exp, err = strconv.Atoi(match[4])
+		if err != nil {
+			return 0, fmt.Errorf("amount %q is out of range", value)
+		}
+	}
+
+	mantissa, _ := new(big.Int).SetString(whole+fraction, 10)
+	if mantissa.Sign() == 0 {
+		return 0, nil
+	}
+
+	// Shift the decimal point so that the result is in hundredths.
+	shift := exp - len(fraction) + 2
+	switch {
+	case shift > maxAmountDigits:
+		return 0, fmt.Errorf("amount %q is out of range", value)
+	case shift >= 0:
+		mantissa.Mul(mantissa, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(shift)), nil))
+	case -shift > len(whole)+len(fraction):
+		// Less than a thousandth of the smallest unit rounds to zero.
+		return 0, nil
+	default:
+		denom := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(-shift)), nil)
+		quo, rem := new(big.Int).QuoRem(mantissa, denom, new(big.Int))
+		if rem.Mul(rem, big.NewInt(2)).Cmp(denom) >= 0 {
+			quo.Add(quo, big.NewInt(1))
+		}
+		mantissa = quo
+	}
+
+	if sign == "-" {
+		mantissa.Neg(mantissa)
+	}
+	if !mantissa.IsInt64() {
+		return 0, fmt.Errorf("amount %q is out of range", value)
+	}
+
+	return Amount(mantissa.Int64()), nil
+}
+
+func (a Amount) String() string {
+	sign := ""
+	value := int64(a)
+	if value < 0 {
+		sign = "-"
+		value = -value
+	}
+
+	whole := strconv.FormatInt(value/amountScale, 10)
+	fraction := value % amountScale
+	if fraction == 0 {
+		return sign + whole
+	}
+
+	return strings.TrimRight(fmt.Sprintf("%s%s.%02d", sign, whole, fraction), "0")
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	// Amounts are JSON numbers; a quoted string is not one.
+	if len(data) == 0 || (data[0] != '-' && (data[0] < '0' || data[0] > '9')) {
+		return fmt.Errorf("amount must be a JSON number, got %s", data)
+	}
+
+	amount, err := ParseAmount(string(data))
+	if err != nil {
+		return err
+	}
+
+	*a = amount
+	return nil
+}
+
+func (a *Amount) Scan(src any) error {
+	switch value := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case int64:
+		*a = Amount(value * amountScale)
+		return nil
+	case float64:
+		amount, err := ParseAmount(strconv.FormatFloat(value, 'f', -1, 64))
+		*a = amount
+		return err
+	case string:
+		amount, err := ParseAmount(value)
+		*a = amount
+		return err
+	case []byte:
+		amount, err := ParseAmount(string(value))
+		*a = amount
+		return err
+	default:
+		return errors.New("unsupported amount type")
+	}
+}
+
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Float64 is for reporting only; arithmetic stays in hundredths.
+func (a Amount) Float64() float64 {
+	return float64(a) / 100
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Amount
+	}{
+		{"729.98", 72998},
+		{"500", 50000},
+		{"0.1", 10},
+		{"-1.05", -105},
+		{"1e2", 10000},
+		{"0.005", 1},
+		{"-0.005", -1},
+		{"0.004", 0},
+		{"12.5e-1", 125},
+		{"1e-300", 0},
+		{"0", 0},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAmount(tt.value)
+		assert.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, got, tt.value)
+	}
+
+	for _, value := range []string{"abc", "1/3", "+5", "05", ".5", "5.", "0x10", "1e400", "Inf", "NaN", ""} {
+		_, err := ParseAmount(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	balance := Balance{Current: 72998, Withdrawn: 50010}
+
+	data, err := json.Marshal(balance)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"current":729.98,"withdrawn":500.1}`, string(data))
+
+	var decoded Balance
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, balance, decoded)
+}
+
+func TestAmount_UnmarshalJSONRejectsNonNumbers(t *testing.T) {
+	for _, data := range []string{`"500"`, `"1/3"`, `"5`, `1/3`, `true`, `{}`, `[]`, ``} {
+		var amount Amount
+		assert.Error(t, amount.UnmarshalJSON([]byte(data)), data)
+		assert.Zero(t, amount, data)
+	}
+
+	var balance Balance
+	assert.Error(t, json.Unmarshal([]byte(`{"current":"500"}`), &balance))
+}
@@ -1,6 +1,6 @@
 package models
 
 type Balance struct {
-	Current   float32 `json:"current"`
-	Withdrawn float32 `json:"withdrawn"`
+	Current   Amount `json:"current"`
+	Withdrawn Amount `json:"withdrawn"`
 }
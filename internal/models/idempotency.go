@@ -0,0 +1,6 @@
+package models
+
+type IdempotentResponse struct {
+	StatusCode int
+	Body       []byte
+}
@@ -0,0 +1,6 @@
+package models
+
+type OrderJob struct {
+	OrderID  OrderID
+	Attempts int
+}
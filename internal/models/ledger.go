@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LedgerEntryType string
+
+const (
+	LedgerAccrual    LedgerEntryType = "accrual"
+	LedgerWithdrawal LedgerEntryType = "withdrawal"
+	LedgerAdjustment LedgerEntryType = "adjustment"
+)
+
+type LedgerEntry struct {
+	TransactionID uuid.UUID       `json:"transaction_id"`
+	EntryType     LedgerEntryType `json:"type"`
+	Amount        Amount          `json:"amount"`
+	OrderID       *OrderID        `json:"order,omitempty"`
+	Reason        string          `json:"reason,omitempty"`
+	CreatedBy     string          `json:"created_by,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+)
+
+// ListQuery selects one page of a user's orders or withdrawals; a zero Limit
+// selects all of them. Sort is the JSON name of the sort field, prefixed with
+// "-" for descending order.
+// TimeZone overrides the user's preferred zone for the returned times.
+type ListQuery struct {
+	Limit    int
+	Sort     string
+	TimeZone string
+	Cursor   *Cursor
+	From     *time.Time
+	To       *time.Time
+	Statuses []OrderStatus
+}
+
+// Cursor points right after the last row of a page: Value is the sort field
+// of that row and ID breaks ties between rows with equal values.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func (c Cursor) String() string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func ParseCursor(value string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, customerror.ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.ID == "" {
+		return nil, customerror.ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
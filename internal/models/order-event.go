@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OrderEvent struct {
+	ID        int64     `json:"id"`
+	UserID    uuid.UUID `json:"-"`
+	OrderID   OrderID   `json:"number"`
+	Status    string    `json:"status"`
+	Accrual   Amount    `json:"accrual"`
+	CreatedAt time.Time `json:"created_at"`
+}
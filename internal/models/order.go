@@ -1,6 +1,8 @@
 package models
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -8,8 +10,8 @@ type OrderID string
 type Order struct {
 	OrderID    OrderID   `json:"number"`
 	Status     string    `json:"status"`
-	Accrual    float32   `json:"accrual"`
-	UploadDate string    `json:"uploaded_at"`
+	Accrual    Amount    `json:"accrual"`
+	UploadDate time.Time `json:"uploaded_at"`
 	UserID     uuid.UUID `json:"-"`
 }
 
@@ -23,7 +25,14 @@ const (
 )
 
 type OrderExternalData struct {
-	Order   string  `json:"order"`
-	Status  string  `json:"status"`
-	Accrual float32 `json:"accrual"`
+	Order   string `json:"order"`
+	Status  string `json:"status"`
+	Accrual Amount `json:"accrual"`
+}
+
+// OrderUploadResult is the outcome for one number of a batch upload; Err is
+// nil for accepted orders.
+type OrderUploadResult struct {
+	OrderID OrderID
+	Err     error
 }
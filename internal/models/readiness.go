@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+const (
+	ReadinessOK          = "ok"
+	ReadinessDegraded    = "degraded"
+	ReadinessUnavailable = "unavailable"
+)
+
+const (
+	CheckOK          = "ok"
+	CheckUnknown     = "unknown"
+	CheckDown        = "down"
+	CheckUnreachable = "unreachable"
+	CheckRateLimited = "rate_limited"
+	CheckBackedUp    = "backed_up"
+)
+
+// Readiness is unavailable only when requests cannot be served at all. An
+// unreachable accrual system or a long job queue degrade it: orders are still
+// accepted and wait to be checked.
+type Readiness struct {
+	Status   string      `json:"status"`
+	Database CheckResult `json:"database"`
+	Accrual  CheckResult `json:"accrual"`
+	Queue    CheckResult `json:"queue"`
+}
+
+type CheckResult struct {
+	Status      string     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	PausedUntil *time.Time `json:"paused_until,omitempty"`
+	Depth       *int       `json:"depth,omitempty"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const RoleAdmin = "admin"
+
+type UserInfo struct {
+	ID           uuid.UUID  `json:"id"`
+	Login        string     `json:"login"`
+	Roles        []string   `json:"roles"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LockedAt     *time.Time `json:"locked_at,omitempty"`
+	LockedReason string     `json:"locked_reason,omitempty"`
+}
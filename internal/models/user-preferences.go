@@ -0,0 +1,5 @@
+package models
+
+type UserPreferences struct {
+	TimeZone string `json:"time_zone"`
+}
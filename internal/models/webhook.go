@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WebhookEventType string
+
+const (
+	WebhookOrderProcessed      WebhookEventType = "order.processed"
+	WebhookOrderInvalid        WebhookEventType = "order.invalid"
+	WebhookWithdrawalSucceeded WebhookEventType = "withdrawal.succeeded"
+)
+
+var WebhookEventTypes = []WebhookEventType{WebhookOrderProcessed, WebhookOrderInvalid, WebhookWithdrawalSucceeded}
+
+type WebhookEndpoint struct {
+	ID         uuid.UUID          `json:"id"`
+	URL        string             `json:"url"`
+	Secret     string             `json:"secret,omitempty"`
+	EventTypes []WebhookEventType `json:"event_types"`
+	CreatedAt  time.Time          `json:"created_at"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookPending   WebhookDeliveryStatus = "pending"
+	WebhookDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDead      WebhookDeliveryStatus = "dead"
+)
+
+type WebhookDelivery struct {
+	ID             int64                 `json:"id"`
+	EndpointID     uuid.UUID             `json:"endpoint_id"`
+	EventType      WebhookEventType      `json:"event_type"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	NextRunAt      time.Time             `json:"next_run_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+
+	// URL and Secret are filled for claimed deliveries only.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookEvent is the body POSTed to webhook endpoints.
+type WebhookEvent struct {
+	ID        uuid.UUID        `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      any              `json:"data"`
+}
+
+type WebhookOrderData struct {
+	OrderID OrderID `json:"number"`
+	Status  string  `json:"status"`
+	Accrual Amount  `json:"accrual"`
+}
@@ -1,7 +1,11 @@
 package models
 
+import (
+	"time"
+)
+
 type Withdrawal struct {
-	OrderID     OrderID `json:"order"`
-	Sum         float32 `json:"sum"`
-	ProcessedAt string  `json:"processed_at"`
+	OrderID     OrderID   `json:"order"`
+	Sum         Amount    `json:"sum"`
+	ProcessedAt time.Time `json:"processed_at"`
 }
@@ -0,0 +1,37 @@
+// Package routing holds what the request middlewares need to know about a
+// request once the chi router has served it.
+package routing
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+)
+
+// Unmatched stands in for the route of requests no pattern matched.
+const Unmatched = "unmatched"
+
+// WrapWriter records the status and size of the response written through it.
+func WrapWriter(w http.ResponseWriter, r *http.Request) middleware.WrapResponseWriter {
+	return middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+}
+
+// Pattern returns the chi route pattern r was served under. It is only known
+// after routing, so middlewares call it once the next handler returns.
+func Pattern(r *http.Request) (string, bool) {
+	routeCtx := chi.RouteContext(r.Context())
+	if routeCtx == nil || routeCtx.RoutePattern() == "" {
+		return Unmatched, false
+	}
+	return routeCtx.RoutePattern(), true
+}
+
+// Status returns the status written to ww, which is 200 when the handler
+// wrote a body or nothing at all without calling WriteHeader.
+func Status(ww middleware.WrapResponseWriter) int {
+	if ww.Status() == 0 {
+		return http.StatusOK
+	}
+	return ww.Status()
+}
@@ -0,0 +1,38 @@
+package routing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatternAndStatus(t *testing.T) {
+	var route string
+	var matched bool
+	var status int
+	record := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ww := WrapWriter(w, r)
+			next.ServeHTTP(ww, r)
+			route, matched = Pattern(r)
+			status = Status(ww)
+		})
+	}
+
+	mux := chi.NewRouter()
+	mux.Use(record)
+	mux.Get(`/api/user/orders/{id}`, func(w http.ResponseWriter, r *http.Request) {})
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/user/orders/12345678903", nil))
+	assert.Equal(t, "/api/user/orders/{id}", route)
+	assert.True(t, matched)
+	assert.Equal(t, http.StatusOK, status)
+
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+	assert.Equal(t, Unmatched, route)
+	assert.False(t, matched)
+	assert.Equal(t, http.StatusNotFound, status)
+}
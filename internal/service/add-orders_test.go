@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestAddOrders_KeepsInputOrder(t *testing.T) {
+	ctrl, mockStorage, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	mockStorage.EXPECT().GetUserID(gomock.Any(), "user1").Return(userID, nil)
+	mockStorage.EXPECT().AddOrders(gomock.Any(), userID, models.StatusNew, []models.OrderID{"12345678903", "4561261212345467"}).
+		Return([]models.OrderUploadResult{
+			{OrderID: "12345678903"},
+			{OrderID: "4561261212345467", Err: customerror.ErrAnotherUserOrder},
+		}, nil)
+
+	results, err := s.AddOrders(context.Background(), "user1", []models.OrderID{"12345678903", "123", "4561261212345467"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.OrderUploadResult{
+		{OrderID: "12345678903"},
+		{OrderID: "123", Err: customerror.ErrWrongOrderFormat},
+		{OrderID: "4561261212345467", Err: customerror.ErrAnotherUserOrder},
+	}, results)
+}
+
+func TestAddOrders_TooLarge(t *testing.T) {
+	ctrl, _, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	_, err := s.AddOrders(context.Background(), "user1", make([]models.OrderID, maxOrderBatchSize+1))
+	assert.ErrorIs(t, err, customerror.ErrBatchTooLarge)
+}
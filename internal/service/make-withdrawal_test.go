@@ -0,0 +1,21 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestMakeWithdrawal_NonPositiveAmount(t *testing.T) {
+	ctrl, _, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	// the storage mock has no expectations: nothing may reach it
+	for _, amount := range []models.Amount{0, -50000} {
+		err := s.MakeWithdrawal(context.Background(), "user1", "2377225624", amount)
+		assert.ErrorIs(t, err, customerror.ErrInvalidWithdrawalSum, "amount %v", amount)
+	}
+}
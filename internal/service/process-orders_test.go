@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/with0p/gophermart/internal/accrual"
+	"github.com/with0p/gophermart/internal/accrual/accrualtest"
+	"github.com/with0p/gophermart/internal/mock"
+	"github.com/with0p/gophermart/internal/models"
+	"github.com/with0p/gophermart/internal/utils"
+)
+
+func setupWorker(t *testing.T) (*gomock.Controller, *mock.MockStorage, *accrualtest.Server, *ServiceGophermart) {
+	ctrl := gomock.NewController(t)
+	mockStorage := mock.NewMockStorage(ctrl)
+	server := accrualtest.NewServer()
+	t.Cleanup(server.Close)
+	s := NewServiceGophermart(mockStorage, accrual.NewHTTPClient(accrual.ClientConfig{BaseURL: server.URL}), utils.NewArgon2idHasher(), Options{})
+	return ctrl, mockStorage, server, &s
+}
+
+func TestProcessOrderJob_Processed(t *testing.T) {
+	ctrl, mockStorage, server, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	server.Script("1230", accrualtest.OK("PROCESSED", 50000))
+	mockStorage.EXPECT().FinishOrderJob(gomock.Any(), models.OrderID("1230"), models.StatusProcessed, models.Amount(50000)).Return(nil)
+
+	s.processOrderJob(context.Background(), models.OrderJob{OrderID: "1230", Attempts: 1})
+}
+
+func TestProcessOrderJob_StillProcessing(t *testing.T) {
+	ctrl, mockStorage, server, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	server.Script("1230", accrualtest.OK("REGISTERED", 0))
+	mockStorage.EXPECT().UpdateOrderStatus(gomock.Any(), models.OrderID("1230"), models.StatusProcessing).Return(nil)
+	mockStorage.EXPECT().RescheduleOrderJob(gomock.Any(), models.OrderID("1230"), gomock.Any(), "").Return(nil)
+
+	s.processOrderJob(context.Background(), models.OrderJob{OrderID: "1230", Attempts: 1})
+}
+
+func TestProcessOrderJob_NotRegistered(t *testing.T) {
+	ctrl, mockStorage, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	mockStorage.EXPECT().RescheduleOrderJob(gomock.Any(), models.OrderID("1230"), gomock.Any(), gomock.Any()).Return(nil)
+
+	s.processOrderJob(context.Background(), models.OrderJob{OrderID: "1230", Attempts: 1})
+}
+
+func TestProcessOrderJob_TooManyRequests(t *testing.T) {
+	ctrl, mockStorage, server, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	server.Script("1230", accrualtest.TooManyRequests(60, 10))
+	mockStorage.EXPECT().RescheduleOrderJob(gomock.Any(), models.OrderID("1230"), gomock.Any(), gomock.Any()).Return(nil)
+
+	s.processOrderJob(context.Background(), models.OrderJob{OrderID: "1230", Attempts: 1})
+
+	pausedFor := time.Until(s.accrualLimiter.PausedUntil())
+	assert.Greater(t, pausedFor, 55*time.Second)
+	assert.Equal(t, 1, server.Requests("1230"))
+}
+
+func TestProcessOrderJob_GivesUpAfterMaxAttempts(t *testing.T) {
+	ctrl, mockStorage, server, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	server.Script("1230", accrualtest.InternalError())
+	mockStorage.EXPECT().KillOrderJob(gomock.Any(), models.OrderID("1230"), gomock.Any()).Return(nil)
+
+	s.processOrderJob(context.Background(), models.OrderJob{OrderID: "1230", Attempts: defaultMaxJobAttempts})
+}
+
+func TestProcessOrderJob_KeepsPollingProcessingOrders(t *testing.T) {
+	ctrl, mockStorage, server, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	server.Script("1230", accrualtest.OK("PROCESSING", 0))
+	mockStorage.EXPECT().UpdateOrderStatus(gomock.Any(), models.OrderID("1230"), models.StatusProcessing).Return(nil)
+	mockStorage.EXPECT().RescheduleOrderJob(gomock.Any(), models.OrderID("1230"), gomock.Any(), "").Return(nil)
+
+	s.processOrderJob(context.Background(), models.OrderJob{OrderID: "1230", Attempts: defaultMaxJobAttempts + 1})
+}
+
+func TestProcessOrderJob_TooManyRequestsAfterMaxAttempts(t *testing.T) {
+	ctrl, mockStorage, server, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	server.Script("1230", accrualtest.TooManyRequests(60, 10))
+	mockStorage.EXPECT().RescheduleOrderJob(gomock.Any(), models.OrderID("1230"), gomock.Any(), gomock.Any()).Return(nil)
+
+	s.processOrderJob(context.Background(), models.OrderJob{OrderID: "1230", Attempts: defaultMaxJobAttempts})
+}
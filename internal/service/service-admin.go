@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func (s *ServiceGophermart) ListUsers(ctx context.Context, search string, limit int, offset int) ([]models.UserInfo, error) {
+	ctx, span := startSpan(ctx, "ListUsers")
+	defer span.End()
+
+	return s.storage.ListUsers(ctx, search, limit, offset)
+}
+
+func (s *ServiceGophermart) GetUserLedger(ctx context.Context, login string) ([]models.LedgerEntry, error) {
+	ctx, span := startSpan(ctx, "GetUserLedger")
+	defer span.End()
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetUserLedger(ctx, userID)
+}
+
+func (s *ServiceGophermart) RecheckOrder(ctx context.Context, orderID models.OrderID) error {
+	ctx, span := startSpan(ctx, "RecheckOrder")
+	defer span.End()
+
+	return s.storage.RequeueOrderJob(ctx, orderID)
+}
+
+func (s *ServiceGophermart) AdjustBalance(ctx context.Context, actor string, login string, amount models.Amount, reason string) error {
+	ctx, span := startSpan(ctx, "AdjustBalance")
+	defer span.End()
+
+	if strings.TrimSpace(reason) == "" {
+		return customerror.ErrReasonRequired
+	}
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.AdjustBalance(ctx, userID, amount, reason, actor)
+}
+
+func (s *ServiceGophermart) LockUser(ctx context.Context, login string, reason string) error {
+	ctx, span := startSpan(ctx, "LockUser")
+	defer span.End()
+
+	if strings.TrimSpace(reason) == "" {
+		return customerror.ErrReasonRequired
+	}
+
+	return s.storage.SetUserLocked(ctx, login, true, reason)
+}
+
+func (s *ServiceGophermart) UnlockUser(ctx context.Context, login string) error {
+	ctx, span := startSpan(ctx, "UnlockUser")
+	defer span.End()
+
+	return s.storage.SetUserLocked(ctx, login, false, "")
+}
+
+func (s *ServiceGophermart) SetUserRoles(ctx context.Context, login string, roles []string) error {
+	ctx, span := startSpan(ctx, "SetUserRoles")
+	defer span.End()
+
+	for _, role := range roles {
+		if role != models.RoleAdmin {
+			return customerror.ErrUnknownRole
+		}
+	}
+
+	return s.storage.SetUserRoles(ctx, login, roles)
+}
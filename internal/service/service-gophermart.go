@@ -2,44 +2,134 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
-	"fmt"
-	"io"
-	"net/http"
 	"strconv"
-	"sync"
 	"time"
 
 	"github.com/theplant/luhn"
+	"github.com/with0p/gophermart/internal/accrual"
 	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/events"
 	"github.com/with0p/gophermart/internal/logger"
 	"github.com/with0p/gophermart/internal/models"
 	"github.com/with0p/gophermart/internal/storage"
 	"github.com/with0p/gophermart/internal/utils"
+	"github.com/with0p/gophermart/internal/webhook"
 )
 
+type Options struct {
+	// AccrualWorkers is how many orders are checked against the accrual
+	// system at once.
+	AccrualWorkers int
+	// QueueSize is the accrual job queue depth at which readiness reports the
+	// queue as backed up.
+	QueueSize int
+	// MaxJobAttempts is how many times an order is claimed before a failed
+	// check stops its retries. Orders still being processed by the accrual
+	// system and rate-limited checks are never given up on.
+	MaxJobAttempts int
+	// WebhookDelivery runs the webhook workers next to the accrual ones.
+	WebhookDelivery bool
+}
+
 type ServiceGophermart struct {
-	storage storage.Storage
+	storage        storage.Storage
+	accrual        accrual.Client
+	accrualLimiter *utils.RateLimiter
+	passwordHasher utils.PasswordHasher
+	orderEvents    *events.Broker
+	webhookSender  webhook.Sender
+	accrualHealth  *accrualHealth
+	workers        *workerPool
+	options        Options
 }
 
-func NewServiceGophermart(currentStorage storage.Storage) ServiceGophermart {
+func NewServiceGophermart(currentStorage storage.Storage, accrualClient accrual.Client, passwordHasher utils.PasswordHasher, options Options) ServiceGophermart {
+	if options.AccrualWorkers <= 0 {
+		options.AccrualWorkers = defaultAccrualWorkers
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = defaultQueueSize
+	}
+	if options.MaxJobAttempts <= 0 {
+		options.MaxJobAttempts = defaultMaxJobAttempts
+	}
 	return ServiceGophermart{
-		storage: currentStorage,
+		storage:        currentStorage,
+		accrual:        accrualClient,
+		accrualLimiter: utils.NewRateLimiter(),
+		passwordHasher: passwordHasher,
+		orderEvents:    events.NewBroker(),
+		webhookSender:  webhook.NewHTTPSender(webhook.SenderConfig{}),
+		accrualHealth:  &accrualHealth{},
+		workers:        &workerPool{},
+		options:        options,
 	}
 }
 
 func (s *ServiceGophermart) RegisterUser(ctx context.Context, login string, password string) error {
-	return s.storage.CreateUser(ctx, login, utils.HashPassword(password))
+	ctx, span := startSpan(ctx, "RegisterUser")
+	defer span.End()
+
+	passwordHash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.CreateUser(ctx, login, passwordHash)
 }
 
 func (s *ServiceGophermart) AuthenticateUser(ctx context.Context, login string, password string) error {
-	return s.storage.ValidateUser(ctx, login, utils.HashPassword(password))
+	ctx, span := startSpan(ctx, "AuthenticateUser")
+	defer span.End()
+
+	passwordHash, err := s.storage.GetUserPasswordHash(ctx, login)
+	if err != nil {
+		return err
+	}
+
+	ok, needsRehash, err := s.passwordHasher.Verify(password, passwordHash)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return customerror.ErrNoSuchUser
+	}
+
+	user, err := s.storage.GetUserInfo(ctx, login)
+	if err != nil {
+		return err
+	}
+	if user.LockedAt != nil {
+		return customerror.ErrUserLocked
+	}
+
+	if needsRehash {
+		newHash, err := s.passwordHasher.Hash(password)
+		if err != nil {
+			logger.ErrorContext(ctx, err)
+			return nil
+		}
+		if err := s.storage.UpdateUserPasswordHash(ctx, login, passwordHash, newHash); err != nil {
+			logger.ErrorContext(ctx, err)
+		}
+	}
+
+	return nil
 }
 
-func (s *ServiceGophermart) AddOrder(ctx context.Context, login string, orderID models.OrderID) error {
+const maxOrderBatchSize = 1000
+
+func validOrderID(orderID models.OrderID) bool {
 	orderIDInt, errInt := strconv.ParseInt(string(orderID), 10, 64)
-	if errInt != nil || !luhn.Valid(int(orderIDInt)) {
+	return errInt == nil && luhn.Valid(int(orderIDInt))
+}
+
+func (s *ServiceGophermart) AddOrder(ctx context.Context, login string, orderID models.OrderID) error {
+	ctx, span := startSpan(ctx, "AddOrder")
+	defer span.End()
+
+	if !validOrderID(orderID) {
 		return customerror.ErrWrongOrderFormat
 	}
 
@@ -64,136 +154,199 @@ func (s *ServiceGophermart) AddOrder(ctx context.Context, login string, orderID
 	return customerror.ErrAlreadyAdded
 }
 
-func (s *ServiceGophermart) GetUserOrders(ctx context.Context, login string) ([]models.Order, error) {
+// AddOrders uploads a batch of orders. Invalid numbers are reported in the
+// results and do not prevent the rest of the batch from being added.
+func (s *ServiceGophermart) AddOrders(ctx context.Context, login string, orderIDs []models.OrderID) ([]models.OrderUploadResult, error) {
+	ctx, span := startSpan(ctx, "AddOrders")
+	defer span.End()
+
+	if len(orderIDs) > maxOrderBatchSize {
+		return nil, customerror.ErrBatchTooLarge
+	}
+
 	userID, errUser := s.storage.GetUserID(ctx, login)
 	if errUser != nil {
 		return nil, errUser
 	}
 
-	orders, err := s.storage.GetUserOrders(ctx, userID)
-	var ordersFormatted = make([]models.Order, len(orders))
-	for i, order := range orders {
-		orderUploadedParsed, err := time.Parse("2006-01-02 15:04:05.999999-07", order.UploadDate)
-		if err != nil {
-			logger.Error(err)
-			continue
+	valid := make([]models.OrderID, 0, len(orderIDs))
+	for _, orderID := range orderIDs {
+		if validOrderID(orderID) {
+			valid = append(valid, orderID)
 		}
-		loc, errLoc := time.LoadLocation("Europe/Moscow")
-		if errLoc != nil {
-			logger.Error(err)
+	}
+
+	added, err := s.storage.AddOrders(ctx, userID, models.StatusNew, valid)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.OrderUploadResult, len(orderIDs))
+	for i, orderID := range orderIDs {
+		if !validOrderID(orderID) {
+			results[i] = models.OrderUploadResult{OrderID: orderID, Err: customerror.ErrWrongOrderFormat}
 			continue
 		}
-		orderUploadedParsed = orderUploadedParsed.In(loc)
-		ordersFormatted[i] = order
-		ordersFormatted[i].UploadDate = orderUploadedParsed.Format(time.RFC3339)
+		results[i] = added[0]
+		added = added[1:]
 	}
 
-	return ordersFormatted, err
+	return results, nil
 }
 
-func (s *ServiceGophermart) FeedQueue(queue chan models.OrderID) {
-	logger.Info("feedQueue")
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
+func (s *ServiceGophermart) GetUserOrders(ctx context.Context, login string, q models.ListQuery) ([]models.Order, *models.Cursor, error) {
+	ctx, span := startSpan(ctx, "GetUserOrders")
+	defer span.End()
+
+	userID, errUser := s.storage.GetUserID(ctx, login)
+	if errUser != nil {
+		return nil, nil, errUser
+	}
 
-	ordersToProcess, err := s.storage.GetUnfinishedOrderIDs(ctx)
+	orders, next, err := s.storage.GetUserOrders(ctx, userID, q)
 	if err != nil {
-		logger.Error(err)
-		return
+		return nil, nil, err
 	}
 
-	for _, order := range ordersToProcess {
-		queue <- order
+	loc, err := s.userLocation(ctx, userID, q.TimeZone)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range orders {
+		orders[i].UploadDate = orders[i].UploadDate.In(loc)
 	}
-}
 
-func (s *ServiceGophermart) ProcessOrders(queue chan models.OrderID, accrualAddr string) {
-	logger.Info("ProcessOrders")
-	ctx := context.Background()
+	return orders, next, nil
+}
 
-	var wg sync.WaitGroup
+const (
+	defaultAccrualWorkers = 3
+	defaultQueueSize      = 1000
+	defaultMaxJobAttempts = 50
+)
 
-	numWorkers := 3
-	semaphore := utils.NewSemaphore(numWorkers)
-	for i := 1; i <= numWorkers; i++ {
-		wg.Add(1)
-		go worker(ctx, queue, &wg, s, semaphore, accrualAddr)
-	}
+const (
+	jobPollInterval      = time.Second
+	jobVisibilityTimeout = 3 * time.Minute
+	jobRetryBaseDelay    = 5 * time.Second
+	jobRetryMaxDelay     = 5 * time.Minute
+)
 
-	wg.Wait()
-	logger.Info("All workers finished processing.")
+// accrualWorker claims jobs until stopCtx is done and processes each with
+// workCtx, so that a job already claimed is seen through on shutdown.
+func (s *ServiceGophermart) accrualWorker(stopCtx context.Context, workCtx context.Context) {
+	logger.InfoContext(stopCtx, "Accrual worker started")
+	defer logger.InfoContext(stopCtx, "Accrual worker stopped")
 
-}
+	for {
+		if err := s.accrualLimiter.Wait(stopCtx); err != nil {
+			return
+		}
 
-func worker(ctx context.Context, jobs chan models.OrderID, wg *sync.WaitGroup, s *ServiceGophermart, sem *utils.Semaphore, accrualAddr string) {
-	defer wg.Done()
-	logger.Info("worker")
-	for orderID := range jobs {
-		sem.Acquire()
-		orderData, err := getOrderDataFromAccrual(ctx, orderID, accrualAddr)
+		jobs, err := s.storage.ClaimOrderJobs(stopCtx, 1, jobVisibilityTimeout)
+		if stopCtx.Err() != nil {
+			return
+		}
 		if err != nil {
-			if errors.Is(err, customerror.ErrTooManyRequests) {
-				sem.Acquire()
-				logger.Info("Too many requests, waiting 60s")
-				time.Sleep(60 * time.Second)
-				sem.Release()
-				logger.Info("Going back to processing")
-				jobs <- orderID
+			logger.ErrorContext(stopCtx, err)
+		}
+		if len(jobs) == 0 {
+			select {
+			case <-stopCtx.Done():
+				return
+			case <-time.After(jobPollInterval):
 			}
-			logger.Error(err)
 			continue
 		}
 
-		errOrd := s.storage.UpdateOrder(ctx, orderID, models.OrderStatus(orderData.Status), float32(orderData.Accrual))
-		if errOrd != nil {
-			logger.Error(errOrd)
-		}
-		sem.Release()
+		s.processOrderJob(workCtx, jobs[0])
 	}
 }
 
-func getOrderDataFromAccrual(ctx context.Context, orderID models.OrderID, accrualAddr string) (*models.OrderExternalData, error) {
-	client := &http.Client{
-		Timeout: 2 * time.Minute,
-	}
+func (s *ServiceGophermart) processOrderJob(ctx context.Context, job models.OrderJob) {
+	ctx, span := startSpan(ctx, "processOrderJob")
+	defer span.End()
+	ctx = logger.With(ctx, "order_id", job.OrderID, "attempts", job.Attempts)
 
-	url := fmt.Sprintf("%s/api/orders/%s", accrualAddr, orderID)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	orderData, err := s.accrual.GetOrder(ctx, job.OrderID)
+	s.accrualHealth.record(err)
 	if err != nil {
-		return nil, err
+		var rateLimitErr *accrual.RateLimitError
+		if errors.As(err, &rateLimitErr) {
+			until := time.Now().Add(rateLimitErr.RetryAfter)
+			s.accrualLimiter.Pause(until, rateLimitErr.RequestsPerMinute)
+			logger.InfoContext(ctx, "Too many requests, accrual paused", "until", until.Format(time.RFC3339), "rpm", rateLimitErr.RequestsPerMinute)
+
+			errResch := s.storage.RescheduleOrderJob(ctx, job.OrderID, until, err.Error())
+			if errResch != nil {
+				logger.ErrorContext(ctx, errResch)
+			}
+			return
+		}
+		logger.ErrorContext(ctx, err)
+		s.rescheduleOrderJob(ctx, job, err.Error())
+		return
 	}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, err
+	status := models.OrderStatus(orderData.Status)
+	if status != models.StatusProcessed && status != models.StatusInvalid {
+		if errOrd := s.storage.UpdateOrderStatus(ctx, job.OrderID, models.StatusProcessing); errOrd != nil {
+			logger.ErrorContext(ctx, errOrd)
+		}
+		s.rescheduleOrderJob(ctx, job, "")
+		return
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	defer resp.Body.Close()
-	if err != nil {
-		return nil, err
+	errOrd := s.storage.FinishOrderJob(ctx, job.OrderID, status, orderData.Accrual)
+	if errOrd != nil {
+		logger.ErrorContext(ctx, errOrd)
+		s.rescheduleOrderJob(ctx, job, errOrd.Error())
 	}
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == http.StatusTooManyRequests {
-			return nil, customerror.ErrTooManyRequests
+}
+
+func (s *ServiceGophermart) rescheduleOrderJob(ctx context.Context, job models.OrderJob, lastError string) {
+	if lastError != "" && job.Attempts >= s.options.MaxJobAttempts {
+		logger.WarnContext(ctx, "Giving up on order", "last_error", lastError)
+		if err := s.storage.KillOrderJob(ctx, job.OrderID, lastError); err != nil {
+			logger.ErrorContext(ctx, err)
 		}
-		return nil, fmt.Errorf("not accepted, status %d", resp.StatusCode)
+		return
 	}
 
-	var orderData models.OrderExternalData
-	err = json.Unmarshal(body, &orderData)
+	err := s.storage.RescheduleOrderJob(ctx, job.OrderID, time.Now().Add(jobRetryDelay(job.Attempts)), lastError)
 	if err != nil {
-		return nil, err
+		logger.ErrorContext(ctx, err)
 	}
+}
 
-	return &orderData, nil
+func jobRetryDelay(attempts int) time.Duration {
+	return backoffDelay(attempts, jobRetryBaseDelay, jobRetryMaxDelay)
+}
+
+// backoffDelay doubles base for every attempt after the first, up to max.
+func backoffDelay(attempts int, base time.Duration, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
 }
 
-func (s *ServiceGophermart) MakeWithdrawal(ctx context.Context, login string, orderID models.OrderID, amount float32) error {
+func (s *ServiceGophermart) MakeWithdrawal(ctx context.Context, login string, orderID models.OrderID, amount models.Amount) error {
+	ctx, span := startSpan(ctx, "MakeWithdrawal")
+	defer span.End()
+
 	orderIDInt, errInt := strconv.ParseInt(string(orderID), 10, 64)
 	if errInt != nil || !luhn.Valid(int(orderIDInt)) {
 		return customerror.ErrWrongOrderFormat
 	}
+	// a negative withdrawal would be booked as a credit
+	if amount <= 0 {
+		return customerror.ErrInvalidWithdrawalSum
+	}
 
 	userID, err := s.storage.GetUserID(ctx, login)
 	if err != nil {
@@ -204,48 +357,74 @@ func (s *ServiceGophermart) MakeWithdrawal(ctx context.Context, login string, or
 }
 
 func (s *ServiceGophermart) GetUserBalance(ctx context.Context, login string) (*models.Balance, error) {
+	ctx, span := startSpan(ctx, "GetUserBalance")
+	defer span.End()
+
 	userID, err := s.storage.GetUserID(ctx, login)
 	if err != nil {
 		return nil, err
 	}
 
-	accrualSum, err := s.storage.GetUserAccrualBalance(ctx, userID)
+	return s.storage.GetUserBalance(ctx, userID)
+}
+
+func (s *ServiceGophermart) GetUserWithdrawals(ctx context.Context, login string, q models.ListQuery) ([]models.Withdrawal, *models.Cursor, error) {
+	ctx, span := startSpan(ctx, "GetUserWithdrawals")
+	defer span.End()
+
+	userID, errUser := s.storage.GetUserID(ctx, login)
+	if errUser != nil {
+		return nil, nil, errUser
+	}
+
+	withdrawals, next, err := s.storage.GetUserWithdrawals(ctx, userID, q)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	withdrawSum, err := s.storage.GetUserWithdrawalSum(ctx, userID)
+	loc, err := s.userLocation(ctx, userID, q.TimeZone)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range withdrawals {
+		withdrawals[i].ProcessedAt = withdrawals[i].ProcessedAt.In(loc)
+	}
+
+	return withdrawals, next, nil
+}
+
+func (s *ServiceGophermart) BeginIdempotentRequest(ctx context.Context, login string, key string, requestHash string) (*models.IdempotentResponse, error) {
+	ctx, span := startSpan(ctx, "BeginIdempotentRequest")
+	defer span.End()
+
+	userID, err := s.storage.GetUserID(ctx, login)
 	if err != nil {
 		return nil, err
 	}
 
-	return &models.Balance{
-		Current:   accrualSum - withdrawSum,
-		Withdrawn: withdrawSum,
-	}, nil
+	return s.storage.BeginIdempotentRequest(ctx, userID, key, requestHash)
 }
 
-func (s *ServiceGophermart) GetUserWithdrawals(ctx context.Context, login string) ([]models.Withdrawal, error) {
-	userID, errUser := s.storage.GetUserID(ctx, login)
-	if errUser != nil {
-		return nil, errUser
+func (s *ServiceGophermart) CompleteIdempotentRequest(ctx context.Context, login string, key string, response models.IdempotentResponse) error {
+	ctx, span := startSpan(ctx, "CompleteIdempotentRequest")
+	defer span.End()
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return err
 	}
 
-	withdrawals, err := s.storage.GetUserWithdrawals(ctx, userID)
-	var withdrawalsFormatted = make([]models.Withdrawal, len(withdrawals))
-	for i, withdrawal := range withdrawals {
-		withdrawalUploadedParsed, err := time.Parse("2006-01-02 15:04:05.999999-07", withdrawal.ProcessedAt)
-		if err != nil {
-			continue
-		}
-		loc, errLoc := time.LoadLocation("Europe/Moscow")
-		if errLoc != nil {
-			continue
-		}
-		withdrawalUploadedParsed = withdrawalUploadedParsed.In(loc)
-		withdrawalsFormatted[i] = withdrawal
-		withdrawalsFormatted[i].ProcessedAt = withdrawalUploadedParsed.Format(time.RFC3339)
+	return s.storage.CompleteIdempotentRequest(ctx, userID, key, response)
+}
+
+func (s *ServiceGophermart) ReleaseIdempotentRequest(ctx context.Context, login string, key string) error {
+	ctx, span := startSpan(ctx, "ReleaseIdempotentRequest")
+	defer span.End()
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return err
 	}
 
-	return withdrawalsFormatted, err
+	return s.storage.ReleaseIdempotentRequest(ctx, userID, key)
 }
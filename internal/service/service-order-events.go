@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/with0p/gophermart/internal/logger"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+const (
+	orderEventsReplayPage      = 500
+	orderEventsRetention       = 7 * 24 * time.Hour
+	orderEventsPruneInterval   = time.Hour
+	orderEventsListenBaseDelay = time.Second
+	orderEventsListenMaxDelay  = 30 * time.Second
+)
+
+// listenOrderEvents relays order events committed by any instance to the
+// streams open on this one, reconnecting until ctx is done.
+func (s *ServiceGophermart) listenOrderEvents(ctx context.Context) {
+	go s.pruneOrderEvents(ctx)
+
+	delay := orderEventsListenBaseDelay
+	for {
+		started := time.Now()
+		err := s.storage.ListenOrderEvents(ctx, s.orderEvents.Publish)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.ErrorContext(ctx, err)
+
+		if time.Since(started) > orderEventsListenMaxDelay {
+			delay = orderEventsListenBaseDelay
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, orderEventsListenMaxDelay)
+	}
+}
+
+func (s *ServiceGophermart) pruneOrderEvents(ctx context.Context) {
+	ticker := time.NewTicker(orderEventsPruneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.storage.PruneOrderEvents(ctx, time.Now().Add(-orderEventsRetention)); err != nil {
+				logger.ErrorContext(ctx, err)
+			}
+		}
+	}
+}
+
+// SubscribeOrderEvents streams the user's order events. With lastEventID set
+// the stored events after it are replayed first. The channel is closed when
+// ctx is done or the subscriber falls behind.
+func (s *ServiceGophermart) SubscribeOrderEvents(ctx context.Context, login string, lastEventID int64) (<-chan models.OrderEvent, error) {
+	ctx, span := startSpan(ctx, "SubscribeOrderEvents")
+	defer span.End()
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	// subscribe before replaying so that nothing committed in between is lost
+	live, unsubscribe := s.orderEvents.Subscribe(userID)
+
+	var replay []models.OrderEvent
+	if lastEventID > 0 {
+		afterID := lastEventID
+		for {
+			page, err := s.storage.GetOrderEvents(ctx, userID, afterID, orderEventsReplayPage)
+			if err != nil {
+				unsubscribe()
+				return nil, err
+			}
+			replay = append(replay, page...)
+			if len(page) < orderEventsReplayPage {
+				break
+			}
+			afterID = page[len(page)-1].ID
+		}
+	}
+
+	out := make(chan models.OrderEvent)
+	go func() {
+		defer close(out)
+		defer unsubscribe()
+
+		sentID := lastEventID
+		send := func(event models.OrderEvent) bool {
+			if event.ID <= sentID {
+				return true
+			}
+			select {
+			case out <- event:
+				sentID = event.ID
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for _, event := range replay {
+			if !send(event) {
+				return
+			}
+		}
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-live:
+				if !ok || !send(event) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestSubscribeOrderEvents_ReplayThenLive(t *testing.T) {
+	ctrl, mockStorage, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	userID := uuid.New()
+	mockStorage.EXPECT().GetUserID(gomock.Any(), "user1").Return(userID, nil)
+	mockStorage.EXPECT().GetOrderEvents(gomock.Any(), userID, int64(10), orderEventsReplayPage).
+		Return([]models.OrderEvent{{ID: 11, UserID: userID}, {ID: 12, UserID: userID}}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := s.SubscribeOrderEvents(ctx, "user1", 10)
+	require.NoError(t, err)
+
+	// already replayed, must not be sent twice
+	s.orderEvents.Publish(models.OrderEvent{ID: 12, UserID: userID})
+	s.orderEvents.Publish(models.OrderEvent{ID: 13, UserID: userID})
+
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		ids = append(ids, (<-events).ID)
+	}
+	assert.Equal(t, []int64{11, 12, 13}, ids)
+
+	cancel()
+	for range events {
+	}
+}
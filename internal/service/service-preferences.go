@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/with0p/gophermart/internal/models"
+	"github.com/with0p/gophermart/internal/utils"
+)
+
+func (s *ServiceGophermart) GetUserPreferences(ctx context.Context, login string) (*models.UserPreferences, error) {
+	ctx, span := startSpan(ctx, "GetUserPreferences")
+	defer span.End()
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetUserPreferences(ctx, userID)
+}
+
+func (s *ServiceGophermart) SetUserPreferences(ctx context.Context, login string, preferences models.UserPreferences) error {
+	ctx, span := startSpan(ctx, "SetUserPreferences")
+	defer span.End()
+
+	if preferences.TimeZone != "" {
+		if _, err := utils.LoadLocation(preferences.TimeZone); err != nil {
+			return err
+		}
+	}
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.SetUserPreferences(ctx, userID, preferences)
+}
+
+// userLocation picks the zone for response times: the one asked for in the
+// request, then the user's preference, then UTC.
+func (s *ServiceGophermart) userLocation(ctx context.Context, userID uuid.UUID, timeZone string) (*time.Location, error) {
+	if timeZone == "" {
+		preferences, err := s.storage.GetUserPreferences(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		timeZone = preferences.TimeZone
+	}
+
+	if timeZone == "" {
+		return time.UTC, nil
+	}
+
+	return utils.LoadLocation(timeZone)
+}
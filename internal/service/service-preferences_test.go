@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestGetUserOrders_TimeZone(t *testing.T) {
+	uploaded := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		query      string
+		preference string
+		want       string
+	}{
+		{name: "default UTC", want: "2024-03-01T09:00:00Z"},
+		{name: "user preference", preference: "Europe/Moscow", want: "2024-03-01T12:00:00+03:00"},
+		{name: "query overrides preference", query: "Asia/Tokyo", preference: "Europe/Moscow", want: "2024-03-01T18:00:00+09:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl, mockStorage, _, s := setupWorker(t)
+			defer ctrl.Finish()
+
+			userID := uuid.New()
+			q := models.ListQuery{Limit: 10, Sort: "-uploaded_at", TimeZone: tt.query}
+			mockStorage.EXPECT().GetUserID(gomock.Any(), "user1").Return(userID, nil)
+			mockStorage.EXPECT().GetUserOrders(gomock.Any(), userID, q).Return([]models.Order{{OrderID: "1", UploadDate: uploaded}}, nil, nil)
+			if tt.query == "" {
+				mockStorage.EXPECT().GetUserPreferences(gomock.Any(), userID).Return(&models.UserPreferences{TimeZone: tt.preference}, nil)
+			}
+
+			orders, _, err := s.GetUserOrders(context.Background(), "user1", q)
+			require.NoError(t, err)
+			require.Len(t, orders, 1)
+			assert.Equal(t, tt.want, orders[0].UploadDate.Format(time.RFC3339))
+		})
+	}
+}
+
+func TestSetUserPreferences_UnknownTimeZone(t *testing.T) {
+	ctrl, _, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	err := s.SetUserPreferences(context.Background(), "user1", models.UserPreferences{TimeZone: "Mars/Olympus_Mons"})
+	assert.ErrorIs(t, err, customerror.ErrUnknownTimeZone)
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+// accrualHealth remembers how the last accrual request went, so that
+// readiness can report on the accrual system without calling it.
+type accrualHealth struct {
+	mu        sync.Mutex
+	checked   bool
+	lastError error
+}
+
+func (h *accrualHealth) record(err error) {
+	if errors.Is(err, customerror.ErrOrderNotRegistered) || errors.Is(err, customerror.ErrTooManyRequests) {
+		// the accrual system answered
+		err = nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.checked = true
+	h.lastError = err
+}
+
+func (h *accrualHealth) state() (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.checked, h.lastError
+}
+
+func (s *ServiceGophermart) CheckReadiness(ctx context.Context) models.Readiness {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	readiness := models.Readiness{
+		Database: models.CheckResult{Status: models.CheckOK},
+		Accrual:  s.checkAccrual(),
+		Queue:    models.CheckResult{Status: models.CheckOK},
+	}
+
+	if err := s.storage.Ping(ctx); err != nil {
+		readiness.Database = models.CheckResult{Status: models.CheckDown, Error: err.Error()}
+	}
+
+	depth, err := s.storage.CountOrderJobs(ctx)
+	switch {
+	case err != nil:
+		readiness.Queue = models.CheckResult{Status: models.CheckUnknown, Error: err.Error()}
+	case depth >= s.options.QueueSize:
+		readiness.Queue = models.CheckResult{Status: models.CheckBackedUp, Depth: &depth}
+	default:
+		readiness.Queue.Depth = &depth
+	}
+
+	switch {
+	case readiness.Database.Status != models.CheckOK:
+		readiness.Status = models.ReadinessUnavailable
+	case readiness.Accrual.Status == models.CheckUnreachable,
+		readiness.Accrual.Status == models.CheckRateLimited,
+		readiness.Queue.Status == models.CheckBackedUp:
+		readiness.Status = models.ReadinessDegraded
+	default:
+		readiness.Status = models.ReadinessOK
+	}
+
+	return readiness
+}
+
+func (s *ServiceGophermart) checkAccrual() models.CheckResult {
+	if pausedUntil := s.accrualLimiter.PausedUntil(); time.Now().Before(pausedUntil) {
+		return models.CheckResult{Status: models.CheckRateLimited, PausedUntil: &pausedUntil}
+	}
+
+	checked, err := s.accrualHealth.state()
+	switch {
+	case !checked:
+		return models.CheckResult{Status: models.CheckUnknown}
+	case err != nil:
+		return models.CheckResult{Status: models.CheckUnreachable, Error: err.Error()}
+	default:
+		return models.CheckResult{Status: models.CheckOK}
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/with0p/gophermart/internal/accrual/accrualtest"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestCheckReadiness_OK(t *testing.T) {
+	ctrl, mockStorage, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	mockStorage.EXPECT().Ping(gomock.Any()).Return(nil)
+	mockStorage.EXPECT().CountOrderJobs(gomock.Any()).Return(3, nil)
+
+	readiness := s.CheckReadiness(context.Background())
+	assert.Equal(t, models.ReadinessOK, readiness.Status)
+	assert.Equal(t, models.CheckUnknown, readiness.Accrual.Status)
+	assert.Equal(t, 3, *readiness.Queue.Depth)
+}
+
+func TestCheckReadiness_DatabaseDown(t *testing.T) {
+	ctrl, mockStorage, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	mockStorage.EXPECT().Ping(gomock.Any()).Return(errors.New("connection refused"))
+	mockStorage.EXPECT().CountOrderJobs(gomock.Any()).Return(0, errors.New("connection refused"))
+
+	readiness := s.CheckReadiness(context.Background())
+	assert.Equal(t, models.ReadinessUnavailable, readiness.Status)
+	assert.Equal(t, models.CheckDown, readiness.Database.Status)
+	assert.Equal(t, models.CheckUnknown, readiness.Queue.Status)
+}
+
+func TestCheckReadiness_AccrualRateLimitedAndQueueBackedUp(t *testing.T) {
+	ctrl, mockStorage, server, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	server.Script("1230", accrualtest.TooManyRequests(60, 10))
+	mockStorage.EXPECT().RescheduleOrderJob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	s.processOrderJob(context.Background(), models.OrderJob{OrderID: "1230", Attempts: 1})
+
+	mockStorage.EXPECT().Ping(gomock.Any()).Return(nil)
+	mockStorage.EXPECT().CountOrderJobs(gomock.Any()).Return(defaultQueueSize, nil)
+
+	readiness := s.CheckReadiness(context.Background())
+	assert.Equal(t, models.ReadinessDegraded, readiness.Status)
+	assert.Equal(t, models.CheckRateLimited, readiness.Accrual.Status)
+	assert.Equal(t, models.CheckBackedUp, readiness.Queue.Status)
+}
+
+func TestCheckReadiness_AccrualUnreachable(t *testing.T) {
+	ctrl, mockStorage, server, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	server.Script("1230", accrualtest.InternalError())
+	mockStorage.EXPECT().RescheduleOrderJob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+	s.processOrderJob(context.Background(), models.OrderJob{OrderID: "1230", Attempts: 1})
+
+	mockStorage.EXPECT().Ping(gomock.Any()).Return(nil)
+	mockStorage.EXPECT().CountOrderJobs(gomock.Any()).Return(0, nil)
+
+	readiness := s.CheckReadiness(context.Background())
+	assert.Equal(t, models.ReadinessDegraded, readiness.Status)
+	assert.Equal(t, models.CheckUnreachable, readiness.Accrual.Status)
+}
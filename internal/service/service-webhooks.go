@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/logger"
+	"github.com/with0p/gophermart/internal/models"
+	"github.com/with0p/gophermart/internal/webhook"
+)
+
+const (
+	webhookPollInterval      = time.Second
+	webhookVisibilityTimeout = time.Minute
+	webhookRetryBaseDelay    = 30 * time.Second
+	webhookRetryMaxDelay     = time.Hour
+	webhookMaxAttempts       = 10
+	webhookDeliveriesPage    = 100
+	webhookWorkers           = 2
+)
+
+func (s *ServiceGophermart) CreateWebhook(ctx context.Context, login string, endpointURL string, eventTypes []models.WebhookEventType) (*models.WebhookEndpoint, error) {
+	ctx, span := startSpan(ctx, "CreateWebhook")
+	defer span.End()
+
+	if err := webhook.ValidateURL(endpointURL); err != nil {
+		return nil, err
+	}
+
+	if len(eventTypes) == 0 {
+		eventTypes = models.WebhookEventTypes
+	}
+	for _, eventType := range eventTypes {
+		if !knownWebhookEvent(eventType) {
+			return nil, customerror.ErrUnknownWebhookEvent
+		}
+	}
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return s.storage.CreateWebhookEndpoint(ctx, userID, models.WebhookEndpoint{
+		URL:        endpointURL,
+		Secret:     "whsec_" + hex.EncodeToString(secret),
+		EventTypes: eventTypes,
+	})
+}
+
+func knownWebhookEvent(eventType models.WebhookEventType) bool {
+	for _, known := range models.WebhookEventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ServiceGophermart) GetWebhooks(ctx context.Context, login string) ([]models.WebhookEndpoint, error) {
+	ctx, span := startSpan(ctx, "GetWebhooks")
+	defer span.End()
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetWebhookEndpoints(ctx, userID)
+}
+
+func (s *ServiceGophermart) DeleteWebhook(ctx context.Context, login string, endpointID uuid.UUID) error {
+	ctx, span := startSpan(ctx, "DeleteWebhook")
+	defer span.End()
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.DeleteWebhookEndpoint(ctx, userID, endpointID)
+}
+
+func (s *ServiceGophermart) GetWebhookDeliveries(ctx context.Context, login string, endpointID uuid.UUID) ([]models.WebhookDelivery, error) {
+	ctx, span := startSpan(ctx, "GetWebhookDeliveries")
+	defer span.End()
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storage.GetWebhookDeliveries(ctx, userID, endpointID, webhookDeliveriesPage)
+}
+
+func (s *ServiceGophermart) ReplayWebhookDelivery(ctx context.Context, login string, deliveryID int64) error {
+	ctx, span := startSpan(ctx, "ReplayWebhookDelivery")
+	defer span.End()
+
+	userID, err := s.storage.GetUserID(ctx, login)
+	if err != nil {
+		return err
+	}
+
+	return s.storage.ReplayWebhookDelivery(ctx, userID, deliveryID)
+}
+
+// webhookWorker claims deliveries until stopCtx is done and sends each with
+// workCtx.
+func (s *ServiceGophermart) webhookWorker(stopCtx context.Context, workCtx context.Context) {
+	for stopCtx.Err() == nil {
+		deliveries, err := s.storage.ClaimWebhookDeliveries(stopCtx, 1, webhookVisibilityTimeout)
+		if err != nil && stopCtx.Err() == nil {
+			logger.ErrorContext(stopCtx, err)
+		}
+		if len(deliveries) == 0 {
+			select {
+			case <-stopCtx.Done():
+			case <-time.After(webhookPollInterval):
+			}
+			continue
+		}
+
+		s.deliverWebhook(workCtx, deliveries[0])
+	}
+}
+
+func (s *ServiceGophermart) deliverWebhook(ctx context.Context, delivery models.WebhookDelivery) {
+	ctx, span := startSpan(ctx, "deliverWebhook")
+	defer span.End()
+	ctx = logger.With(ctx, "delivery_id", delivery.ID, "attempts", delivery.Attempts)
+
+	statusCode, err := s.webhookSender.Send(ctx, delivery)
+
+	status, runAt, lastError := models.WebhookDelivered, time.Now(), ""
+	if err != nil {
+		lastError = err.Error()
+		status = models.WebhookPending
+		runAt = time.Now().Add(backoffDelay(delivery.Attempts, webhookRetryBaseDelay, webhookRetryMaxDelay))
+		if delivery.Attempts >= webhookMaxAttempts {
+			status = models.WebhookDead
+		}
+	}
+
+	if err := s.storage.FinishWebhookDelivery(ctx, delivery.ID, status, runAt, statusCode, lastError); err != nil {
+		logger.ErrorContext(ctx, err)
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+type fakeSender struct {
+	status int
+	err    error
+}
+
+func (f fakeSender) Send(context.Context, models.WebhookDelivery) (int, error) {
+	return f.status, f.err
+}
+
+func TestDeliverWebhook_Delivered(t *testing.T) {
+	ctrl, mockStorage, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	s.webhookSender = fakeSender{status: http.StatusOK}
+	mockStorage.EXPECT().FinishWebhookDelivery(gomock.Any(), int64(1), models.WebhookDelivered, gomock.Any(), http.StatusOK, "").Return(nil)
+
+	s.deliverWebhook(context.Background(), models.WebhookDelivery{ID: 1, Attempts: 1})
+}
+
+func TestDeliverWebhook_Retry(t *testing.T) {
+	ctrl, mockStorage, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	s.webhookSender = fakeSender{status: http.StatusBadGateway, err: errors.New("bad gateway")}
+	mockStorage.EXPECT().FinishWebhookDelivery(gomock.Any(), int64(1), models.WebhookPending, gomock.Any(), http.StatusBadGateway, "bad gateway").Return(nil)
+
+	s.deliverWebhook(context.Background(), models.WebhookDelivery{ID: 1, Attempts: 3})
+}
+
+func TestDeliverWebhook_DeadLetter(t *testing.T) {
+	ctrl, mockStorage, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	s.webhookSender = fakeSender{err: errors.New("connection refused")}
+	mockStorage.EXPECT().FinishWebhookDelivery(gomock.Any(), int64(1), models.WebhookDead, gomock.Any(), 0, "connection refused").Return(nil)
+
+	s.deliverWebhook(context.Background(), models.WebhookDelivery{ID: 1, Attempts: webhookMaxAttempts})
+}
+
+func TestCreateWebhook_Validation(t *testing.T) {
+	ctrl, _, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	_, err := s.CreateWebhook(context.Background(), "user1", "ftp://example.com/hook", nil)
+	assert.ErrorIs(t, err, customerror.ErrInvalidWebhookURL)
+
+	_, err = s.CreateWebhook(context.Background(), "user1", "http://example.com/hook", nil)
+	assert.ErrorIs(t, err, customerror.ErrInvalidWebhookURL)
+
+	_, err = s.CreateWebhook(context.Background(), "user1", "https://169.254.169.254/latest/meta-data", nil)
+	assert.ErrorIs(t, err, customerror.ErrForbiddenWebhookTarget)
+
+	_, err = s.CreateWebhook(context.Background(), "user1", "https://example.com/hook", []models.WebhookEventType{"order.created"})
+	assert.ErrorIs(t, err, customerror.ErrUnknownWebhookEvent)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/logger"
+)
+
+// workerPool tracks the background workers. Stopping is two-staged: the stop
+// context tells the workers not to pick up anything new, while the work
+// context, which the job at hand runs with, is only canceled once the Stop
+// deadline passes.
+type workerPool struct {
+	mu      sync.Mutex
+	started bool
+	stop    context.CancelFunc
+	abort   context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// Start runs the accrual workers, the order event listener and, with
+// Options.WebhookDelivery, the webhook workers until Stop is called or ctx is
+// done.
+func (s *ServiceGophermart) Start(ctx context.Context) error {
+	p := s.workers
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.started {
+		return customerror.ErrWorkersStarted
+	}
+	p.started = true
+
+	stopCtx, stop := context.WithCancel(ctx)
+	workCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	p.stop, p.abort = stop, abort
+
+	for i := 1; i <= s.options.AccrualWorkers; i++ {
+		p.run(func() {
+			s.accrualWorker(logger.With(stopCtx, "worker", i), logger.With(workCtx, "worker", i))
+		})
+	}
+	p.run(func() { s.listenOrderEvents(stopCtx) })
+	if s.options.WebhookDelivery {
+		for i := 1; i <= webhookWorkers; i++ {
+			p.run(func() {
+				s.webhookWorker(logger.With(stopCtx, "webhook_worker", i), logger.With(workCtx, "webhook_worker", i))
+			})
+		}
+	}
+
+	logger.Info("Workers started", "accrual_workers", s.options.AccrualWorkers, "webhook_delivery", s.options.WebhookDelivery)
+	return nil
+}
+
+// Stop asks the workers to finish the job at hand and waits for them. Once ctx
+// is done the jobs still running are canceled; their claims expire and they
+// are picked up again later.
+func (s *ServiceGophermart) Stop(ctx context.Context) error {
+	p := s.workers
+	p.mu.Lock()
+	if !p.started {
+		p.mu.Unlock()
+		return customerror.ErrWorkersNotStarted
+	}
+	p.started = false
+	stop, abort := p.stop, p.abort
+	p.mu.Unlock()
+
+	stop()
+	defer abort()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info("All workers finished processing.")
+		return nil
+	case <-ctx.Done():
+		abort()
+		<-done
+		return errors.Join(customerror.ErrWorkersStopTimeout, fmt.Errorf("in-flight jobs canceled: %w", ctx.Err()))
+	}
+}
+
+func (p *workerPool) run(worker func()) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		worker()
+	}()
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/with0p/gophermart/internal/accrual/accrualtest"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/mock"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+// expectOneJob makes the storage hand out a single job and block the event
+// listener until it is stopped.
+func expectOneJob(mockStorage *mock.MockStorage) {
+	gomock.InOrder(
+		mockStorage.EXPECT().ClaimOrderJobs(gomock.Any(), 1, jobVisibilityTimeout).Return([]models.OrderJob{{OrderID: "1230", Attempts: 1}}, nil),
+		mockStorage.EXPECT().ClaimOrderJobs(gomock.Any(), 1, jobVisibilityTimeout).Return(nil, nil).AnyTimes(),
+	)
+	mockStorage.EXPECT().ListenOrderEvents(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ func(models.OrderEvent)) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+}
+
+func TestWorkers_StopDrainsInFlightJob(t *testing.T) {
+	ctrl, mockStorage, server, s := setupWorker(t)
+	defer ctrl.Finish()
+	s.options.AccrualWorkers = 1
+
+	server.Script("1230", accrualtest.OK("PROCESSED", 50000))
+	expectOneJob(mockStorage)
+	claimed, release := make(chan struct{}), make(chan struct{})
+	mockStorage.EXPECT().FinishOrderJob(gomock.Any(), models.OrderID("1230"), models.StatusProcessed, models.Amount(50000)).
+		DoAndReturn(func(ctx context.Context, _ models.OrderID, _ models.OrderStatus, _ models.Amount) error {
+			close(claimed)
+			<-release
+			return ctx.Err()
+		})
+
+	require.NoError(t, s.Start(context.Background()))
+	assert.ErrorIs(t, s.Start(context.Background()), customerror.ErrWorkersStarted)
+	<-claimed
+
+	stopped := make(chan error)
+	go func() { stopped <- s.Stop(context.Background()) }()
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned before the job at hand was finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	assert.NoError(t, <-stopped)
+}
+
+func TestWorkers_StopCancelsJobsAfterDeadline(t *testing.T) {
+	ctrl, mockStorage, server, s := setupWorker(t)
+	defer ctrl.Finish()
+	s.options.AccrualWorkers = 1
+
+	server.Script("1230", accrualtest.OK("PROCESSED", 50000))
+	expectOneJob(mockStorage)
+	claimed := make(chan struct{})
+	mockStorage.EXPECT().FinishOrderJob(gomock.Any(), models.OrderID("1230"), models.StatusProcessed, models.Amount(50000)).
+		DoAndReturn(func(ctx context.Context, _ models.OrderID, _ models.OrderStatus, _ models.Amount) error {
+			close(claimed)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	mockStorage.EXPECT().RescheduleOrderJob(gomock.Any(), models.OrderID("1230"), gomock.Any(), gomock.Any()).Return(nil)
+
+	require.NoError(t, s.Start(context.Background()))
+	<-claimed
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := s.Stop(ctx)
+	assert.ErrorIs(t, err, customerror.ErrWorkersStopTimeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWorkers_StopWithoutStart(t *testing.T) {
+	ctrl, _, _, s := setupWorker(t)
+	defer ctrl.Finish()
+
+	assert.ErrorIs(t, s.Stop(context.Background()), customerror.ErrWorkersNotStarted)
+}
@@ -3,6 +3,8 @@ package service
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"github.com/with0p/gophermart/internal/models"
 )
 
@@ -10,10 +12,41 @@ type Service interface {
 	RegisterUser(ctx context.Context, login string, password string) error
 	AuthenticateUser(ctx context.Context, login string, password string) error
 	AddOrder(ctx context.Context, login string, orderID models.OrderID) error
-	GetUserOrders(ctx context.Context, login string) ([]models.Order, error)
-	ProcessOrders(queue chan models.OrderID, accrualAddr string)
-	FeedQueue(queue chan models.OrderID)
-	MakeWithdrawal(ctx context.Context, login string, orderID models.OrderID, amount float32) error
+	AddOrders(ctx context.Context, login string, orderIDs []models.OrderID) ([]models.OrderUploadResult, error)
+	GetUserOrders(ctx context.Context, login string, q models.ListQuery) ([]models.Order, *models.Cursor, error)
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+	SubscribeOrderEvents(ctx context.Context, login string, lastEventID int64) (<-chan models.OrderEvent, error)
+	MakeWithdrawal(ctx context.Context, login string, orderID models.OrderID, amount models.Amount) error
+	GetUserBalance(ctx context.Context, login string) (*models.Balance, error)
+	GetUserWithdrawals(ctx context.Context, login string, q models.ListQuery) ([]models.Withdrawal, *models.Cursor, error)
+	GetUserPreferences(ctx context.Context, login string) (*models.UserPreferences, error)
+	SetUserPreferences(ctx context.Context, login string, preferences models.UserPreferences) error
+	CreateWebhook(ctx context.Context, login string, endpointURL string, eventTypes []models.WebhookEventType) (*models.WebhookEndpoint, error)
+	GetWebhooks(ctx context.Context, login string) ([]models.WebhookEndpoint, error)
+	DeleteWebhook(ctx context.Context, login string, endpointID uuid.UUID) error
+	GetWebhookDeliveries(ctx context.Context, login string, endpointID uuid.UUID) ([]models.WebhookDelivery, error)
+	ReplayWebhookDelivery(ctx context.Context, login string, deliveryID int64) error
+	BeginIdempotentRequest(ctx context.Context, login string, key string, requestHash string) (*models.IdempotentResponse, error)
+	CompleteIdempotentRequest(ctx context.Context, login string, key string, response models.IdempotentResponse) error
+	ReleaseIdempotentRequest(ctx context.Context, login string, key string) error
+	CheckReadiness(ctx context.Context) models.Readiness
+}
+
+type AdminService interface {
+	ListUsers(ctx context.Context, search string, limit int, offset int) ([]models.UserInfo, error)
+	GetUserOrders(ctx context.Context, login string, q models.ListQuery) ([]models.Order, *models.Cursor, error)
+	GetUserWithdrawals(ctx context.Context, login string, q models.ListQuery) ([]models.Withdrawal, *models.Cursor, error)
 	GetUserBalance(ctx context.Context, login string) (*models.Balance, error)
-	GetUserWithdrawals(ctx context.Context, login string) ([]models.Withdrawal, error)
+	GetUserLedger(ctx context.Context, login string) ([]models.LedgerEntry, error)
+	RecheckOrder(ctx context.Context, orderID models.OrderID) error
+	AdjustBalance(ctx context.Context, actor string, login string, amount models.Amount, reason string) error
+	LockUser(ctx context.Context, login string, reason string) error
+	UnlockUser(ctx context.Context, login string) error
+	SetUserRoles(ctx context.Context, login string, roles []string) error
+	CreateWebhook(ctx context.Context, login string, endpointURL string, eventTypes []models.WebhookEventType) (*models.WebhookEndpoint, error)
+	GetWebhooks(ctx context.Context, login string) ([]models.WebhookEndpoint, error)
+	DeleteWebhook(ctx context.Context, login string, endpointID uuid.UUID) error
+	GetWebhookDeliveries(ctx context.Context, login string, endpointID uuid.UUID) ([]models.WebhookDelivery, error)
+	ReplayWebhookDelivery(ctx context.Context, login string, deliveryID int64) error
 }
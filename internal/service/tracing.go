@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/with0p/gophermart/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func startSpan(ctx context.Context, method string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "ServiceGophermart."+method)
+}
@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func splitRoles(roles string) []string {
+	if roles == "" {
+		return []string{}
+	}
+	return strings.Split(roles, ",")
+}
+
+func (s *StorageDB) ListUsers(ctx context.Context, search string, limit int, offset int) ([]models.UserInfo, error) {
+	query := `
+	SELECT id, login, array_to_string(roles, ','), created_at, locked_at, COALESCE(locked_reason, '')
+	FROM user_auth
+	WHERE login ILIKE '%' || $1 || '%'
+	ORDER BY login
+	LIMIT $2 OFFSET $3;`
+
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(search)
+	rows, err := s.db.QueryContext(ctx, query, escaped, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.UserInfo{}
+
+	for rows.Next() {
+		var user models.UserInfo
+		var roles string
+		err := rows.Scan(&user.ID, &user.Login, &roles, &user.CreatedAt, &user.LockedAt, &user.LockedReason)
+		if err != nil {
+			return nil, err
+		}
+		user.Roles = splitRoles(roles)
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (s *StorageDB) GetUserInfo(ctx context.Context, login string) (*models.UserInfo, error) {
+	query := `
+	SELECT id, login, array_to_string(roles, ','), created_at, locked_at, COALESCE(locked_reason, '')
+	FROM user_auth
+	WHERE login = $1;`
+
+	var user models.UserInfo
+	var roles string
+	err := s.db.QueryRowContext(ctx, query, login).Scan(&user.ID, &user.Login, &roles, &user.CreatedAt, &user.LockedAt, &user.LockedReason)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerror.ErrNoSuchUser
+		}
+		return nil, err
+	}
+	user.Roles = splitRoles(roles)
+
+	return &user, nil
+}
+
+func (s *StorageDB) GetUserRoles(ctx context.Context, login string) ([]string, error) {
+	user, err := s.GetUserInfo(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	return user.Roles, nil
+}
+
+func (s *StorageDB) SetUserRoles(ctx context.Context, login string, roles []string) error {
+	query := `
+	UPDATE user_auth
+	SET roles = string_to_array(NULLIF($2, ''), ',')
+	WHERE login = $1;`
+
+	result, err := s.db.ExecContext(ctx, query, login, strings.Join(roles, ","))
+	if err != nil {
+		return err
+	}
+
+	return expectOneRow(result, customerror.ErrNoSuchUser)
+}
+
+// SetUserLocked locks or unlocks the user. Locking also revokes every
+// session of the user.
+func (s *StorageDB) SetUserLocked(ctx context.Context, login string, locked bool, reason string) error {
+	tr, errTr := s.db.BeginTx(ctx, nil)
+	if errTr != nil {
+		return errTr
+	}
+
+	query := `
+	UPDATE user_auth
+	SET locked_at = CASE WHEN $2 THEN NOW() END, locked_reason = CASE WHEN $2 THEN $3 END
+	WHERE login = $1;`
+
+	result, err := tr.ExecContext(ctx, query, login, locked, reason)
+	if err != nil {
+		tr.Rollback()
+		return err
+	}
+	if err := expectOneRow(result, customerror.ErrNoSuchUser); err != nil {
+		tr.Rollback()
+		return err
+	}
+
+	if locked {
+		queryRevoke := `
+		UPDATE auth_sessions SET revoked_at = NOW()
+		WHERE user_id = (SELECT id FROM user_auth WHERE login = $1) AND revoked_at IS NULL;`
+		_, err := tr.ExecContext(ctx, queryRevoke, login)
+		if err != nil {
+			tr.Rollback()
+			return err
+		}
+	}
+
+	return tr.Commit()
+}
+
+func (s *StorageDB) AdjustBalance(ctx context.Context, userID uuid.UUID, amount models.Amount, reason string, createdBy string) error {
+	tr, errTr := s.db.BeginTx(ctx, nil)
+	if errTr != nil {
+		return errTr
+	}
+
+	balance, err := lockUserBalance(ctx, tr, userID)
+	if err != nil {
+		tr.Rollback()
+		return err
+	}
+
+	if balance+amount < 0 {
+		tr.Rollback()
+		return customerror.ErrInsufficientBalance
+	}
+
+	_, err = postLedgerTransaction(ctx, tr, userID, models.LedgerAdjustment, amount, nil, reason, createdBy)
+	if err != nil {
+		tr.Rollback()
+		return err
+	}
+
+	return tr.Commit()
+}
+
+func (s *StorageDB) GetUserLedger(ctx context.Context, userID uuid.UUID) ([]models.LedgerEntry, error) {
+	query := `
+	SELECT transaction_id, entry_type, amount, order_id, COALESCE(reason, ''), COALESCE(created_by, ''), created_at
+	FROM ledger_entries
+	WHERE user_id = $1 AND account = $2
+	ORDER BY created_at DESC, id DESC;`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, pointsAccount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.LedgerEntry{}
+
+	for rows.Next() {
+		var entry models.LedgerEntry
+		var orderID sql.NullString
+		err := rows.Scan(&entry.TransactionID, &entry.EntryType, &entry.Amount, &orderID, &entry.Reason, &entry.CreatedBy, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		if orderID.Valid {
+			id := models.OrderID(orderID.String)
+			entry.OrderID = &id
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// RequeueOrderJob schedules the order for an immediate check against the
+// accrual system, final orders included: FinishOrderJob settles the ledger
+// by difference. A job a worker holds keeps its lock and is run again once
+// released.
+func (s *StorageDB) RequeueOrderJob(ctx context.Context, orderID models.OrderID) error {
+	tr, errTr := s.db.BeginTx(ctx, nil)
+	if errTr != nil {
+		return errTr
+	}
+
+	var locked int
+	err := tr.QueryRowContext(ctx, `SELECT 1 FROM user_orders WHERE order_id = $1 FOR UPDATE`, orderID).Scan(&locked)
+	if err != nil {
+		tr.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return customerror.ErrNoSuchOrder
+		}
+		return err
+	}
+
+	query := `
+	INSERT INTO accrual_jobs (order_id)
+	VALUES ($1)
+	ON CONFLICT (order_id) DO UPDATE
+	SET next_run_at = NOW(), attempts = 0, last_error = NULL, dead = FALSE;`
+	if _, err := tr.ExecContext(ctx, query, orderID); err != nil {
+		tr.Rollback()
+		return err
+	}
+
+	return tr.Commit()
+}
+
+func expectOneRow(result sql.Result, errNotFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errNotFound
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+const pointsAccount = "points"
+
+// postLedgerTransaction records amount as a movement on the user's points
+// account balanced by the counter account of entryType, and applies it to
+// the user's materialized balance. A positive amount credits the user.
+// Withdrawals tied to an order are recorded at most once; it reports whether
+// the movement was recorded.
+func postLedgerTransaction(ctx context.Context, tr *sql.Tx, userID uuid.UUID, entryType models.LedgerEntryType, amount models.Amount, orderID *models.OrderID, reason string, createdBy string) (bool, error) {
+	query := `
+	INSERT INTO ledger_entries (transaction_id, user_id, account, entry_type, amount, order_id, reason, created_by)
+	VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, '')),
+		($1, $2, $4, $4, -$5::numeric, $6, NULLIF($7, ''), NULLIF($8, ''))
+	ON CONFLICT DO NOTHING;`
+
+	result, err := tr.ExecContext(ctx, query, uuid.New(), userID, pointsAccount, entryType, amount, orderID, reason, createdBy)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if inserted == 0 {
+		return false, nil
+	}
+
+	withdrawn := models.Amount(0)
+	if entryType == models.LedgerWithdrawal {
+		withdrawn = -amount
+	}
+
+	queryBalance := `
+	INSERT INTO user_balances (user_id, current, withdrawn, updated_at)
+	VALUES ($1, $2, $3, NOW())
+	ON CONFLICT (user_id) DO UPDATE
+	SET current = user_balances.current + EXCLUDED.current,
+		withdrawn = user_balances.withdrawn + EXCLUDED.withdrawn,
+		updated_at = NOW();`
+	_, err = tr.ExecContext(ctx, queryBalance, userID, amount, withdrawn)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// settleOrderAccrual brings the accrual credited for the order to what its
+// status earns and returns the amount posted. The caller holds the order's
+// row lock, so checking an order again never credits it twice; a lower
+// accrual is taken back even if that leaves the balance negative.
+func settleOrderAccrual(ctx context.Context, tr *sql.Tx, userID uuid.UUID, orderID models.OrderID, status models.OrderStatus, accrual models.Amount) (models.Amount, error) {
+	earned := models.Amount(0)
+	if status == models.StatusProcessed {
+		earned = accrual
+	}
+
+	query := `
+	SELECT COALESCE(SUM(amount), 0)
+	FROM ledger_entries
+	WHERE order_id = $1 AND entry_type = $2 AND account = $3;`
+
+	var credited models.Amount
+	err := tr.QueryRowContext(ctx, query, orderID, models.LedgerAccrual, pointsAccount).Scan(&credited)
+	if err != nil || credited == earned {
+		return 0, err
+	}
+
+	if _, err := postLedgerTransaction(ctx, tr, userID, models.LedgerAccrual, earned-credited, &orderID, "", ""); err != nil {
+		return 0, err
+	}
+	return earned - credited, nil
+}
+
+func lockUserBalance(ctx context.Context, tr *sql.Tx, userID uuid.UUID) (models.Amount, error) {
+	_, err := tr.ExecContext(ctx, `INSERT INTO user_balances (user_id) VALUES ($1) ON CONFLICT DO NOTHING`, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	var current models.Amount
+	err = tr.QueryRowContext(ctx, `SELECT current FROM user_balances WHERE user_id = $1 FOR UPDATE`, userID).Scan(&current)
+
+	return current, err
+}
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/with0p/gophermart/internal/models"
+)
+
+type sortColumn struct {
+	column string
+	cast   string
+}
+
+type listTable struct {
+	name        string
+	idColumn    string
+	dateColumn  string
+	sortColumns map[string]sortColumn
+}
+
+var ordersTable = listTable{
+	name:       "user_orders",
+	idColumn:   "order_id",
+	dateColumn: "uploaded_at",
+	sortColumns: map[string]sortColumn{
+		"uploaded_at": {column: "uploaded_at", cast: "timestamptz"},
+		"accrual":     {column: "accrual", cast: "numeric"},
+	},
+}
+
+var withdrawalsTable = listTable{
+	name:       "user_withdrawals",
+	idColumn:   "order_id",
+	dateColumn: "added_at",
+	sortColumns: map[string]sortColumn{
+		"processed_at": {column: "added_at", cast: "timestamptz"},
+		"sum":          {column: "withdrawal_amount", cast: "numeric"},
+	},
+}
+
+type listQueryBuilder struct {
+	conditions []string
+	args       []any
+}
+
+func (b *listQueryBuilder) arg(value any) string {
+	b.args = append(b.args, value)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+func (b *listQueryBuilder) where(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// build selects columns plus the sort value as text, which becomes the
+// cursor of the next page. One row more than the limit is requested to know
+// whether a next page exists; without a limit every row is.
+func (b *listQueryBuilder) build(table listTable, columns string, q models.ListQuery) (string, error) {
+	desc := strings.HasPrefix(q.Sort, "-")
+	sort, ok := table.sortColumns[strings.TrimPrefix(q.Sort, "-")]
+	if !ok {
+		return "", fmt.Errorf("unknown sort field %q", q.Sort)
+	}
+
+	if q.From != nil {
+		b.where(table.dateColumn + " >= " + b.arg(*q.From))
+	}
+	if q.To != nil {
+		b.where(table.dateColumn + " < " + b.arg(*q.To))
+	}
+
+	direction, compare := "ASC", ">"
+	if desc {
+		direction, compare = "DESC", "<"
+	}
+
+	if q.Cursor != nil {
+		b.where(fmt.Sprintf("(%s, %s) %s (%s::%s, %s)",
+			sort.column, table.idColumn, compare, b.arg(q.Cursor.Value), sort.cast, b.arg(q.Cursor.ID)))
+	}
+
+	limit := ""
+	if q.Limit > 0 {
+		limit = "\n\tLIMIT " + b.arg(q.Limit+1)
+	}
+
+	query := fmt.Sprintf(`SELECT %s, %s, %s::text
+	FROM %s
+	WHERE %s
+	ORDER BY %s %s, %s %s%s;`,
+		columns, table.idColumn, sort.column,
+		table.name,
+		strings.Join(b.conditions, " AND "),
+		sort.column, direction, table.idColumn, direction,
+		limit)
+
+	return query, nil
+}
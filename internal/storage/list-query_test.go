@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestListQueryBuilder_Cursor(t *testing.T) {
+	userID := uuid.New()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	b := &listQueryBuilder{}
+	b.where("user_id = " + b.arg(userID))
+	query, err := b.build(ordersTable, "status", models.ListQuery{
+		Limit:  10,
+		Sort:   "-uploaded_at",
+		From:   &from,
+		Cursor: &models.Cursor{Sort: "-uploaded_at", Value: "2024-02-01 10:00:00+00", ID: "12345678903"},
+	})
+	require.NoError(t, err)
+
+	assert.Contains(t, query, "WHERE user_id = $1 AND uploaded_at >= $2 AND (uploaded_at, order_id) < ($3::timestamptz, $4)")
+	assert.Contains(t, query, "ORDER BY uploaded_at DESC, order_id DESC")
+	assert.Contains(t, query, "LIMIT $5;")
+	assert.Equal(t, []any{userID, from, "2024-02-01 10:00:00+00", "12345678903", 11}, b.args)
+}
+
+func TestListQueryBuilder_Ascending(t *testing.T) {
+	b := &listQueryBuilder{}
+	b.where("user_id = " + b.arg(uuid.New()))
+	query, err := b.build(withdrawalsTable, "withdrawal_amount", models.ListQuery{Limit: 5, Sort: "sum"})
+	require.NoError(t, err)
+
+	assert.Contains(t, query, "SELECT withdrawal_amount, order_id, withdrawal_amount::text")
+	assert.Contains(t, query, "ORDER BY withdrawal_amount ASC, order_id ASC")
+}
+
+func TestListQueryBuilder_Unlimited(t *testing.T) {
+	b := &listQueryBuilder{}
+	b.where("user_id = " + b.arg(uuid.New()))
+	query, err := b.build(ordersTable, "status", models.ListQuery{Sort: "-uploaded_at"})
+	require.NoError(t, err)
+
+	assert.NotContains(t, query, "LIMIT")
+	assert.Len(t, b.args, 1)
+}
+
+func TestListQueryBuilder_UnknownSort(t *testing.T) {
+	b := &listQueryBuilder{}
+	_, err := b.build(ordersTable, "status", models.ListQuery{Limit: 5, Sort: "status; DROP TABLE user_orders"})
+	assert.Error(t, err)
+}
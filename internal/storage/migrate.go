@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// migrationLockID serializes concurrent migration runs across instances.
+const migrationLockID = 7261830
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationState struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(files, path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s must have both up and down steps", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			return nil, fmt.Errorf("migration versions must be sequential, got %d at position %d", migration.Version, i+1)
+		}
+	}
+
+	return migrations, nil
+}
+
+func ensureSchemaVersionTable(ctx context.Context, db *sql.DB) error {
+	query := `
+	CREATE TABLE IF NOT EXISTS schema_version (
+		version INT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	);`
+	_, err := db.ExecContext(ctx, query)
+
+	return err
+}
+
+func currentSchemaVersion(ctx context.Context, q interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}) (int, error) {
+	var version int
+	err := q.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_version`).Scan(&version)
+
+	return version, err
+}
+
+func MigrateUp(ctx context.Context, db *sql.DB) error {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureSchemaVersionTable(ctx, db); err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if err := applyMigration(ctx, db, migration, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func MigrateDown(ctx context.Context, db *sql.DB) error {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureSchemaVersionTable(ctx, db); err != nil {
+		return err
+	}
+
+	version, err := currentSchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if version == 0 {
+		return nil
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("%w: database is at version %d, latest known is %d", customerror.ErrSchemaMismatch, version, len(migrations))
+	}
+
+	return applyMigration(ctx, db, migrations[version-1], false)
+}
+
+func applyMigration(ctx context.Context, db *sql.DB, migration Migration, up bool) error {
+	tr, errTr := db.BeginTx(ctx, nil)
+	if errTr != nil {
+		return errTr
+	}
+
+	if _, err := tr.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, migrationLockID); err != nil {
+		tr.Rollback()
+		return err
+	}
+
+	version, err := currentSchemaVersion(ctx, tr)
+	if err != nil {
+		tr.Rollback()
+		return err
+	}
+
+	if (up && version >= migration.Version) || (!up && version != migration.Version) {
+		return tr.Rollback()
+	}
+
+	step := migration.Down
+	if up {
+		step = migration.Up
+	}
+	if _, err := tr.ExecContext(ctx, step); err != nil {
+		tr.Rollback()
+		return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+
+	if up {
+		_, err = tr.ExecContext(ctx, `INSERT INTO schema_version (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+	} else {
+		_, err = tr.ExecContext(ctx, `DELETE FROM schema_version WHERE version = $1`, migration.Version)
+	}
+	if err != nil {
+		tr.Rollback()
+		return err
+	}
+
+	return tr.Commit()
+}
+
+func GetMigrationStatus(ctx context.Context, db *sql.DB) ([]MigrationState, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureSchemaVersionTable(ctx, db); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(ctx, `SELECT version, applied_at FROM schema_version`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[int]time.Time)
+	for rows.Next() {
+		var version int
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	states := make([]MigrationState, len(migrations))
+	for i, migration := range migrations {
+		states[i].Migration = migration
+		if appliedAt, ok := applied[migration.Version]; ok {
+			states[i].AppliedAt = &appliedAt
+		}
+	}
+
+	return states, nil
+}
+
+func CheckSchemaVersion(ctx context.Context, db *sql.DB) error {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return err
+	}
+
+	var exists bool
+	err = db.QueryRowContext(ctx, `SELECT to_regclass('schema_version') IS NOT NULL`).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	version := 0
+	if exists {
+		version, err = currentSchemaVersion(ctx, db)
+		if err != nil {
+			return err
+		}
+	}
+
+	if version != len(migrations) {
+		return fmt.Errorf("%w: database is at version %d, expected %d; run \"gophermart migrate up\"", customerror.ErrSchemaMismatch, version, len(migrations))
+	}
+
+	return nil
+}
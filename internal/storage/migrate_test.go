@@ -0,0 +1,45 @@
+package storage
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, migrations)
+	for i, migration := range migrations {
+		assert.Equal(t, i+1, migration.Version)
+		assert.NotEmpty(t, migration.Up)
+		assert.NotEmpty(t, migration.Down)
+	}
+}
+
+func TestLoadMigrations_MissingDown(t *testing.T) {
+	files := fstest.MapFS{
+		"migrations/0001_init.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+		"migrations/0001_init.down.sql": {Data: []byte("DROP TABLE a;")},
+		"migrations/0002_next.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+	}
+
+	_, err := loadMigrations(files)
+
+	assert.Error(t, err)
+}
+
+func TestLoadMigrations_Gap(t *testing.T) {
+	files := fstest.MapFS{
+		"migrations/0001_init.up.sql":   {Data: []byte("CREATE TABLE a ();")},
+		"migrations/0001_init.down.sql": {Data: []byte("DROP TABLE a;")},
+		"migrations/0003_next.up.sql":   {Data: []byte("CREATE TABLE b ();")},
+		"migrations/0003_next.down.sql": {Data: []byte("DROP TABLE b;")},
+	}
+
+	_, err := loadMigrations(files)
+
+	assert.Error(t, err)
+}
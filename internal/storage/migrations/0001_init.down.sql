@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_withdrawals;
+DROP TABLE IF EXISTS user_orders;
+DROP TABLE IF EXISTS user_auth;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS user_auth (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    login TEXT NOT NULL,
+    password TEXT NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS user_login_index ON user_auth (login);
+
+CREATE TABLE IF NOT EXISTS user_orders (
+    order_id TEXT PRIMARY KEY,
+    status TEXT NOT NULL,
+    accrual NUMERIC(14, 2) DEFAULT 0,
+    uploaded_at TIMESTAMPTZ,
+    user_id UUID NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS user_withdrawals (
+    order_id TEXT PRIMARY KEY,
+    withdrawal_amount NUMERIC(14, 2) NOT NULL,
+    added_at TIMESTAMPTZ,
+    user_id UUID NOT NULL
+);
+
+-- databases created before versioned migrations stored amounts as FLOAT4
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'user_orders' AND column_name = 'accrual' AND data_type = 'real') THEN
+        ALTER TABLE user_orders ALTER COLUMN accrual TYPE NUMERIC(14, 2) USING ROUND(accrual::float8::numeric, 2);
+    END IF;
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'user_withdrawals' AND column_name = 'withdrawal_amount' AND data_type = 'real') THEN
+        ALTER TABLE user_withdrawals ALTER COLUMN withdrawal_amount TYPE NUMERIC(14, 2) USING ROUND(withdrawal_amount::float8::numeric, 2);
+    END IF;
+END $$;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS accrual_jobs (
+    order_id TEXT PRIMARY KEY,
+    attempts INT NOT NULL DEFAULT 0,
+    next_run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ,
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS accrual_jobs_next_run_index ON accrual_jobs (next_run_at);
+
+INSERT INTO accrual_jobs (order_id)
+SELECT order_id FROM user_orders WHERE status IN ('NEW', 'PROCESSING')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_balances;
+DROP TABLE IF EXISTS ledger_entries;
@@ -0,0 +1,47 @@
+-- Every point movement is a transaction of two entries that sum to zero:
+-- one on the user's "points" account and one on the counter account named
+-- after the entry type. The "points" account is what the user can spend.
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    account TEXT NOT NULL,
+    entry_type TEXT NOT NULL,
+    amount NUMERIC(14, 2) NOT NULL,
+    order_id TEXT,
+    reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS ledger_entries_user_index ON ledger_entries (user_id, created_at);
+CREATE INDEX IF NOT EXISTS ledger_entries_transaction_index ON ledger_entries (transaction_id);
+CREATE UNIQUE INDEX IF NOT EXISTS ledger_entries_order_index ON ledger_entries (entry_type, account, order_id)
+    WHERE order_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS user_balances (
+    user_id UUID PRIMARY KEY,
+    current NUMERIC(14, 2) NOT NULL DEFAULT 0,
+    withdrawn NUMERIC(14, 2) NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TEMPORARY TABLE ledger_backfill ON COMMIT DROP AS
+SELECT gen_random_uuid() AS transaction_id, user_id, 'accrual' AS entry_type, accrual AS amount,
+    order_id, COALESCE(uploaded_at, NOW()) AS created_at
+FROM user_orders
+WHERE status = 'PROCESSED' AND accrual > 0
+UNION ALL
+SELECT gen_random_uuid(), user_id, 'withdrawal', -withdrawal_amount, order_id, COALESCE(added_at, NOW())
+FROM user_withdrawals;
+
+INSERT INTO ledger_entries (transaction_id, user_id, account, entry_type, amount, order_id, created_at)
+SELECT transaction_id, user_id, 'points', entry_type, amount, order_id, created_at FROM ledger_backfill
+UNION ALL
+SELECT transaction_id, user_id, entry_type, entry_type, -amount, order_id, created_at FROM ledger_backfill;
+
+INSERT INTO user_balances (user_id, current, withdrawn)
+SELECT user_id,
+    SUM(amount) FILTER (WHERE account = 'points'),
+    COALESCE(SUM(amount) FILTER (WHERE account = 'withdrawal'), 0)
+FROM ledger_entries
+GROUP BY user_id
+ON CONFLICT (user_id) DO NOTHING;
@@ -0,0 +1,7 @@
+ALTER TABLE ledger_entries DROP COLUMN IF EXISTS created_by;
+
+ALTER TABLE user_auth
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS locked_reason,
+    DROP COLUMN IF EXISTS locked_at,
+    DROP COLUMN IF EXISTS roles;
@@ -0,0 +1,7 @@
+ALTER TABLE user_auth
+    ADD COLUMN IF NOT EXISTS roles TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS locked_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS locked_reason TEXT,
+    ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+ALTER TABLE ledger_entries ADD COLUMN IF NOT EXISTS created_by TEXT;
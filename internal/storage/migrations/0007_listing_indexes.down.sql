@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS user_withdrawals_user_amount_index;
+DROP INDEX IF EXISTS user_withdrawals_user_added_index;
+DROP INDEX IF EXISTS user_orders_user_accrual_index;
+DROP INDEX IF EXISTS user_orders_user_uploaded_index;
+
+ALTER TABLE user_withdrawals ALTER COLUMN added_at DROP NOT NULL;
+ALTER TABLE user_orders ALTER COLUMN uploaded_at DROP NOT NULL;
@@ -0,0 +1,15 @@
+-- keyset pagination compares (sort column, order_id) tuples, so the date
+-- columns must not be NULL
+UPDATE user_orders SET uploaded_at = NOW() WHERE uploaded_at IS NULL;
+ALTER TABLE user_orders ALTER COLUMN uploaded_at SET NOT NULL;
+UPDATE user_withdrawals SET added_at = NOW() WHERE added_at IS NULL;
+ALTER TABLE user_withdrawals ALTER COLUMN added_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS user_orders_user_uploaded_index
+    ON user_orders (user_id, uploaded_at, order_id) INCLUDE (status, accrual);
+CREATE INDEX IF NOT EXISTS user_orders_user_accrual_index
+    ON user_orders (user_id, accrual, order_id) INCLUDE (status, uploaded_at);
+CREATE INDEX IF NOT EXISTS user_withdrawals_user_added_index
+    ON user_withdrawals (user_id, added_at, order_id) INCLUDE (withdrawal_amount);
+CREATE INDEX IF NOT EXISTS user_withdrawals_user_amount_index
+    ON user_withdrawals (user_id, withdrawal_amount, order_id) INCLUDE (added_at);
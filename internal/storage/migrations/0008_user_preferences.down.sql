@@ -0,0 +1 @@
+ALTER TABLE user_auth DROP COLUMN IF EXISTS time_zone;
@@ -0,0 +1 @@
+ALTER TABLE user_auth ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT '';
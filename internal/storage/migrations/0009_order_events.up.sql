@@ -0,0 +1,12 @@
+-- every status or accrual change of an order; ids double as SSE event ids,
+-- so clients resume a stream with the last id they saw
+CREATE TABLE IF NOT EXISTS order_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    order_id TEXT NOT NULL,
+    status TEXT NOT NULL,
+    accrual NUMERIC(14, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS order_events_user_index ON order_events (user_id, id);
+CREATE INDEX IF NOT EXISTS order_events_created_index ON order_events (created_at);
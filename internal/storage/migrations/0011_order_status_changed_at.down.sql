@@ -0,0 +1 @@
+ALTER TABLE user_orders DROP COLUMN IF EXISTS status_changed_at;
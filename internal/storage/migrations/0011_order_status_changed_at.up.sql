@@ -0,0 +1,6 @@
+-- when the order entered its current status, for the time-in-status metrics
+ALTER TABLE user_orders ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ;
+UPDATE user_orders SET status_changed_at = uploaded_at WHERE status_changed_at IS NULL;
+ALTER TABLE user_orders
+    ALTER COLUMN status_changed_at SET DEFAULT NOW(),
+    ALTER COLUMN status_changed_at SET NOT NULL;
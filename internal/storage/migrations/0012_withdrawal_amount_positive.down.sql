@@ -0,0 +1 @@
+ALTER TABLE user_withdrawals DROP CONSTRAINT IF EXISTS user_withdrawals_amount_positive;
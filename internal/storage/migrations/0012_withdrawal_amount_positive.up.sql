@@ -0,0 +1,4 @@
+-- NOT VALID keeps the migration from failing on rows written before the
+-- service rejected non-positive sums; new rows are checked either way
+ALTER TABLE user_withdrawals
+    ADD CONSTRAINT user_withdrawals_amount_positive CHECK (withdrawal_amount > 0) NOT VALID;
@@ -0,0 +1,3 @@
+-- jobs that ran out of attempts stay for inspection and admin re-checks but
+-- are no longer claimed or counted as queued
+ALTER TABLE accrual_jobs ADD COLUMN IF NOT EXISTS dead BOOLEAN NOT NULL DEFAULT FALSE;
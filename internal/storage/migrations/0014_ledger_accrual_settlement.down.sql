@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS ledger_entries_order_accrual_index;
+DROP INDEX IF EXISTS ledger_entries_order_index;
+CREATE UNIQUE INDEX IF NOT EXISTS ledger_entries_order_index ON ledger_entries (entry_type, account, order_id)
+    WHERE order_id IS NOT NULL;
@@ -0,0 +1,7 @@
+-- a re-checked order may correct its accrual, so accrual entries are settled
+-- by difference under the order's row lock rather than kept unique per order
+DROP INDEX IF EXISTS ledger_entries_order_index;
+CREATE UNIQUE INDEX IF NOT EXISTS ledger_entries_order_index ON ledger_entries (entry_type, account, order_id)
+    WHERE order_id IS NOT NULL AND entry_type <> 'accrual';
+CREATE INDEX IF NOT EXISTS ledger_entries_order_accrual_index ON ledger_entries (order_id)
+    WHERE entry_type = 'accrual';
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+const orderEventsChannel = "order_events"
+
+// OrderEvent.UserID is not part of its JSON, so notifications carry it
+// separately.
+type orderEventNotification struct {
+	UserID uuid.UUID         `json:"user_id"`
+	Event  models.OrderEvent `json:"event"`
+}
+
+// recordOrderEvent stores the change and notifies listeners once tr commits.
+func recordOrderEvent(ctx context.Context, tr *sql.Tx, userID uuid.UUID, orderID models.OrderID, status models.OrderStatus, accrual models.Amount) error {
+	event := models.OrderEvent{UserID: userID, OrderID: orderID, Status: string(status), Accrual: accrual}
+
+	query := `
+	INSERT INTO order_events (user_id, order_id, status, accrual)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, created_at;`
+	err := tr.QueryRowContext(ctx, query, userID, orderID, status, accrual).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(orderEventNotification{UserID: userID, Event: event})
+	if err != nil {
+		return err
+	}
+
+	_, err = tr.ExecContext(ctx, `SELECT pg_notify($1, $2);`, orderEventsChannel, string(payload))
+	return err
+}
+
+func (s *StorageDB) GetOrderEvents(ctx context.Context, userID uuid.UUID, afterID int64, limit int) ([]models.OrderEvent, error) {
+	query := `
+	SELECT id, order_id, status, accrual, created_at
+	FROM order_events
+	WHERE user_id = $1 AND id > $2
+	ORDER BY id
+	LIMIT $3;`
+
+	rows, err := s.db.QueryContext(ctx, query, userID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.OrderEvent
+
+	for rows.Next() {
+		event := models.OrderEvent{UserID: userID}
+		err := rows.Scan(&event.ID, &event.OrderID, &event.Status, &event.Accrual, &event.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s *StorageDB) PruneOrderEvents(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM order_events WHERE created_at < $1;`, before)
+	return err
+}
+
+// ListenOrderEvents holds a dedicated connection listening for order events
+// and calls handle for each of them. It returns when ctx is done or the
+// connection fails.
+func (s *StorageDB) ListenOrderEvents(ctx context.Context, handle func(models.OrderEvent)) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("listening for notifications requires the pgx driver")
+		}
+		pgxConn := stdlibConn.Conn()
+
+		if _, err := pgxConn.Exec(ctx, "LISTEN "+orderEventsChannel); err != nil {
+			return err
+		}
+
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+
+			var payload orderEventNotification
+			if err := json.Unmarshal([]byte(notification.Payload), &payload); err != nil {
+				continue
+			}
+			payload.Event.UserID = payload.UserID
+			handle(payload.Event)
+		}
+	})
+}
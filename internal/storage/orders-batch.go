@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+// AddOrders inserts the orders and their accrual jobs in one transaction.
+// Numbers that are already taken are reported per order instead of failing
+// the batch.
+func (s *StorageDB) AddOrders(ctx context.Context, userID uuid.UUID, status models.OrderStatus, orderIDs []models.OrderID) ([]models.OrderUploadResult, error) {
+	tr, errTr := s.db.BeginTx(ctx, nil)
+	if errTr != nil {
+		return nil, errTr
+	}
+
+	queryOrder := `
+	INSERT INTO user_orders (order_id, status, uploaded_at, user_id)
+	VALUES ($1, $2, NOW(), $3)
+	ON CONFLICT (order_id) DO NOTHING;`
+	queryOwner := `SELECT user_id FROM user_orders WHERE order_id = $1`
+	queryJob := `
+	INSERT INTO accrual_jobs (order_id)
+	VALUES ($1)
+	ON CONFLICT DO NOTHING;`
+
+	results := make([]models.OrderUploadResult, len(orderIDs))
+	for i, orderID := range orderIDs {
+		results[i].OrderID = orderID
+
+		result, err := tr.ExecContext(ctx, queryOrder, orderID, status, userID)
+		if err != nil {
+			tr.Rollback()
+			return nil, err
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			tr.Rollback()
+			return nil, err
+		}
+
+		if affected == 0 {
+			var ownerID uuid.UUID
+			if err := tr.QueryRowContext(ctx, queryOwner, orderID).Scan(&ownerID); err != nil {
+				tr.Rollback()
+				return nil, err
+			}
+			if ownerID != userID {
+				results[i].Err = customerror.ErrAnotherUserOrder
+			} else {
+				results[i].Err = customerror.ErrAlreadyAdded
+			}
+			continue
+		}
+
+		if _, err := tr.ExecContext(ctx, queryJob, orderID); err != nil {
+			tr.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tr.Commit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
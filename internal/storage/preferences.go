@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func (s *StorageDB) GetUserPreferences(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error) {
+	query := `SELECT time_zone FROM user_auth WHERE id = $1`
+
+	var preferences models.UserPreferences
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(&preferences.TimeZone)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, customerror.ErrNoSuchUser
+		}
+		return nil, err
+	}
+
+	return &preferences, nil
+}
+
+func (s *StorageDB) SetUserPreferences(ctx context.Context, userID uuid.UUID, preferences models.UserPreferences) error {
+	query := `UPDATE user_auth SET time_zone = $2 WHERE id = $1`
+
+	result, err := s.db.ExecContext(ctx, query, userID, preferences.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	return expectOneRow(result, customerror.ErrNoSuchUser)
+}
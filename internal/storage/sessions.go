@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+)
+
+func (s *StorageDB) CreateSession(ctx context.Context, login string, refreshTokenHash string, expiresAt time.Time) (uuid.UUID, error) {
+	tr, errTr := s.db.BeginTx(ctx, nil)
+	if errTr != nil {
+		return uuid.Nil, errTr
+	}
+
+	querySession := `
+	INSERT INTO auth_sessions (user_id, expires_at)
+	SELECT id, $2 FROM user_auth WHERE login = $1
+	RETURNING id;`
+
+	var sessionID uuid.UUID
+	err := tr.QueryRowContext(ctx, querySession, login, expiresAt).Scan(&sessionID)
+	if err != nil {
+		tr.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, customerror.ErrNoSuchUser
+		}
+		return uuid.Nil, err
+	}
+
+	queryToken := `
+	INSERT INTO refresh_tokens (token_hash, session_id, expires_at)
+	VALUES ($1, $2, $3);`
+	_, err = tr.ExecContext(ctx, queryToken, refreshTokenHash, sessionID, expiresAt)
+	if err != nil {
+		tr.Rollback()
+		return uuid.Nil, err
+	}
+
+	return sessionID, tr.Commit()
+}
+
+func (s *StorageDB) RotateRefreshToken(ctx context.Context, oldHash string, newHash string, expiresAt time.Time) (uuid.UUID, string, error) {
+	tr, errTr := s.db.BeginTx(ctx, nil)
+	if errTr != nil {
+		return uuid.Nil, "", errTr
+	}
+
+	querySelect := `
+	SELECT t.session_id, t.used_at IS NOT NULL, t.expires_at <= NOW(), s.revoked_at IS NOT NULL, u.login
+	FROM refresh_tokens t
+	JOIN auth_sessions s ON s.id = t.session_id
+	JOIN user_auth u ON u.id = s.user_id
+	WHERE t.token_hash = $1
+	FOR UPDATE OF t, s;`
+
+	var sessionID uuid.UUID
+	var used, expired, revoked bool
+	var login string
+	err := tr.QueryRowContext(ctx, querySelect, oldHash).Scan(&sessionID, &used, &expired, &revoked, &login)
+	if err != nil {
+		tr.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, "", customerror.ErrInvalidRefreshToken
+		}
+		return uuid.Nil, "", err
+	}
+
+	if used {
+		_, err := tr.ExecContext(ctx, `UPDATE auth_sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`, sessionID)
+		if err != nil {
+			tr.Rollback()
+			return uuid.Nil, "", err
+		}
+		if err := tr.Commit(); err != nil {
+			return uuid.Nil, "", err
+		}
+		return uuid.Nil, "", customerror.ErrRefreshTokenReused
+	}
+
+	if expired || revoked {
+		tr.Rollback()
+		return uuid.Nil, "", customerror.ErrInvalidRefreshToken
+	}
+
+	_, err = tr.ExecContext(ctx, `UPDATE refresh_tokens SET used_at = NOW() WHERE token_hash = $1`, oldHash)
+	if err != nil {
+		tr.Rollback()
+		return uuid.Nil, "", err
+	}
+
+	queryToken := `
+	INSERT INTO refresh_tokens (token_hash, session_id, expires_at)
+	VALUES ($1, $2, $3);`
+	_, err = tr.ExecContext(ctx, queryToken, newHash, sessionID, expiresAt)
+	if err != nil {
+		tr.Rollback()
+		return uuid.Nil, "", err
+	}
+
+	_, err = tr.ExecContext(ctx, `UPDATE auth_sessions SET last_used_at = NOW(), expires_at = $2 WHERE id = $1`, sessionID, expiresAt)
+	if err != nil {
+		tr.Rollback()
+		return uuid.Nil, "", err
+	}
+
+	return sessionID, login, tr.Commit()
+}
+
+func (s *StorageDB) IsSessionActive(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM auth_sessions
+		WHERE id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+	);`
+
+	var active bool
+	err := s.db.QueryRowContext(ctx, query, sessionID).Scan(&active)
+
+	return active, err
+}
+
+func (s *StorageDB) RevokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	query := `UPDATE auth_sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL`
+	_, err := s.db.ExecContext(ctx, query, sessionID)
+
+	return err
+}
+
+func (s *StorageDB) RevokeUserSessions(ctx context.Context, login string) error {
+	query := `
+	UPDATE auth_sessions SET revoked_at = NOW()
+	WHERE user_id = (SELECT id FROM user_auth WHERE login = $1) AND revoked_at IS NULL;`
+	_, err := s.db.ExecContext(ctx, query, login)
+
+	return err
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgerrcode"
 
 	"github.com/jackc/pgx/v5/pgconn"
 	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/metrics"
 	"github.com/with0p/gophermart/internal/models"
 )
 
@@ -18,50 +21,13 @@ type StorageDB struct {
 }
 
 func NewStorageDB(ctx context.Context, db *sql.DB) (*StorageDB, error) {
-	err := initTable(ctx, db)
+	err := CheckSchemaVersion(ctx, db)
 	if err != nil {
 		return nil, err
 	}
 	return &StorageDB{db: db}, nil
 }
 
-func initTable(ctx context.Context, db *sql.DB) error {
-	tr, errTr := db.BeginTx(ctx, nil)
-	if errTr != nil {
-		return errTr
-	}
-
-	queryUserTable := `
-    CREATE TABLE IF NOT EXISTS user_auth (
-        id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
-        login TEXT NOT NULL,
-        password TEXT NOT NULL
-    );`
-	tr.ExecContext(ctx, queryUserTable)
-	tr.ExecContext(ctx, `CREATE UNIQUE INDEX IF NOT EXISTS user_login_index ON user_auth (login)`)
-
-	queryOrderTable := `
-	CREATE TABLE IF NOT EXISTS user_orders (
-		order_id TEXT PRIMARY KEY,
-		status TEXT NOT NULL,
-		accrual FLOAT4 DEFAULT 0,
-		uploaded_at TIMESTAMPTZ,
-		user_id UUID NOT NULL
-	);`
-	tr.ExecContext(ctx, queryOrderTable)
-
-	queryUserWithdrawals := `
-	CREATE TABLE IF NOT EXISTS user_withdrawals (
-    order_id TEXT PRIMARY KEY,
-    withdrawal_amount FLOAT4 NOT NULL,
-    added_at TIMESTAMPTZ,
-    user_id UUID NOT NULL
-	);`
-	tr.ExecContext(ctx, queryUserWithdrawals)
-
-	return tr.Commit()
-}
-
 func (s *StorageDB) CreateUser(ctx context.Context, login string, password string) error {
 	query := `
 		INSERT INTO user_auth (login, password)
@@ -79,19 +45,26 @@ func (s *StorageDB) CreateUser(ctx context.Context, login string, password strin
 	return err
 }
 
-func (s *StorageDB) ValidateUser(ctx context.Context, login string, password string) error {
-	query := `SELECT id::text FROM user_auth WHERE login = $1 AND password = $2`
+func (s *StorageDB) GetUserPasswordHash(ctx context.Context, login string) (string, error) {
+	query := `SELECT password FROM user_auth WHERE login = $1`
 
-	var userID string
-	err := s.db.QueryRowContext(ctx, query, login, password).Scan(&userID)
+	var passwordHash string
+	err := s.db.QueryRowContext(ctx, query, login).Scan(&passwordHash)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return customerror.ErrNoSuchUser
+			return "", customerror.ErrNoSuchUser
 		}
-		return err
+		return "", err
 	}
 
-	return nil
+	return passwordHash, nil
+}
+
+func (s *StorageDB) UpdateUserPasswordHash(ctx context.Context, login string, oldHash string, newHash string) error {
+	query := `UPDATE user_auth SET password = $3 WHERE login = $1 AND password = $2`
+	_, err := s.db.ExecContext(ctx, query, login, oldHash, newHash)
+
+	return err
 }
 
 func (s *StorageDB) GetUserID(ctx context.Context, login string) (uuid.UUID, error) {
@@ -110,7 +83,7 @@ func (s *StorageDB) GetUserID(ctx context.Context, login string) (uuid.UUID, err
 }
 
 func (s *StorageDB) GetOrder(ctx context.Context, orderID models.OrderID) (*models.Order, error) {
-	query := `SELECT order_id, status, accrual, user_id, uploaded_at::text
+	query := `SELECT order_id, status, accrual, user_id, uploaded_at
 	FROM user_orders WHERE order_id = $1`
 	var order models.Order
 	err := s.db.QueryRowContext(ctx, query, orderID).Scan(&order.OrderID, &order.Status, &order.Accrual, &order.UserID, &order.UploadDate)
@@ -125,128 +98,282 @@ func (s *StorageDB) GetOrder(ctx context.Context, orderID models.OrderID) (*mode
 }
 
 func (s *StorageDB) AddOrder(ctx context.Context, userID uuid.UUID, status models.OrderStatus, orderID models.OrderID) error {
-	query := `
+	tr, errTr := s.db.BeginTx(ctx, nil)
+	if errTr != nil {
+		return errTr
+	}
+
+	queryOrder := `
 	INSERT INTO user_orders (order_id, status, uploaded_at, user_id)
     VALUES ($1, $2, NOW(), $3);`
-	_, err := s.db.ExecContext(ctx, query, orderID, status, userID)
+	_, err := tr.ExecContext(ctx, queryOrder, orderID, status, userID)
+	if err != nil {
+		tr.Rollback()
+		return err
+	}
 
-	return err
+	queryJob := `
+	INSERT INTO accrual_jobs (order_id)
+	VALUES ($1)
+	ON CONFLICT DO NOTHING;`
+	_, err = tr.ExecContext(ctx, queryJob, orderID)
+	if err != nil {
+		tr.Rollback()
+		return err
+	}
+
+	return tr.Commit()
 }
 
-func (s *StorageDB) UpdateOrder(ctx context.Context, orderID models.OrderID, status models.OrderStatus, accrual float32) error {
+// UpdateOrderStatus moves an order that is not final yet to status, keeping
+// its accrual. An order event is only recorded when the status actually
+// changes, since the worker re-applies PROCESSING on every poll; a final
+// order, possibly finished by a worker that claimed the job after this one's
+// lease ran out, is left alone.
+func (s *StorageDB) UpdateOrderStatus(ctx context.Context, orderID models.OrderID, status models.OrderStatus) error {
+	tr, errTr := s.db.BeginTx(ctx, nil)
+	if errTr != nil {
+		return errTr
+	}
+
 	query := `
-	UPDATE user_orders
-	SET status = $2, accrual = $3
-	WHERE order_id = $1;`
-	_, err := s.db.ExecContext(ctx, query, orderID, status, accrual)
+	UPDATE user_orders o
+	SET status = $2, status_changed_at = NOW()
+	FROM (SELECT status, status_changed_at FROM user_orders WHERE order_id = $1 FOR UPDATE) old
+	WHERE o.order_id = $1 AND old.status NOT IN ('PROCESSED', 'INVALID') AND old.status IS DISTINCT FROM $2
+	RETURNING o.user_id, COALESCE(o.accrual, 0), old.status, EXTRACT(EPOCH FROM NOW() - old.status_changed_at)::float8;`
+	var userID uuid.UUID
+	var accrual models.Amount
+	var transition orderTransition
+	err := tr.QueryRowContext(ctx, query, orderID, status).Scan(&userID, &accrual, &transition.from, &transition.seconds)
+	if err != nil {
+		tr.Rollback()
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
 
-	return err
+	if err := recordOrderEvent(ctx, tr, userID, orderID, status, accrual); err != nil {
+		tr.Rollback()
+		return err
+	}
+
+	if err := tr.Commit(); err != nil {
+		return err
+	}
+
+	transition.observe(status)
+	return nil
 }
 
-func (s *StorageDB) GetUserOrders(ctx context.Context, userID uuid.UUID) ([]models.Order, error) {
-	query := `SELECT order_id, status, accrual, uploaded_at::text
-	FROM user_orders
-	WHERE user_id = $1
-	ORDER BY uploaded_at DESC;`
+func (s *StorageDB) GetUserOrders(ctx context.Context, userID uuid.UUID, q models.ListQuery) ([]models.Order, *models.Cursor, error) {
+	b := &listQueryBuilder{}
+	b.where("user_id = " + b.arg(userID))
+	if len(q.Statuses) > 0 {
+		statuses := make([]string, len(q.Statuses))
+		for i, status := range q.Statuses {
+			statuses[i] = b.arg(status)
+		}
+		b.where("status IN (" + strings.Join(statuses, ", ") + ")")
+	}
+
+	query, err := b.build(ordersTable, "status, accrual, uploaded_at", q)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	rows, err := s.db.QueryContext(ctx, query, userID)
+	rows, err := s.db.QueryContext(ctx, query, b.args...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var orders []models.Order
+	var sortValue string
 
 	for rows.Next() {
 		var order models.Order
-		err := rows.Scan(&order.OrderID, &order.Status, &order.Accrual, &order.UploadDate)
+		var value string
+		err := rows.Scan(&order.Status, &order.Accrual, &order.UploadDate, &order.OrderID, &value)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		if len(orders) < q.Limit {
+			sortValue = value
 		}
 		orders = append(orders, order)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return orders, nil
-}
+	if q.Limit == 0 || len(orders) <= q.Limit {
+		return orders, nil, nil
+	}
+	orders = orders[:q.Limit]
 
-func (s *StorageDB) GetUnfinishedOrderIDs(ctx context.Context) ([]models.OrderID, error) {
-	query := `SELECT order_id
-	FROM user_orders
-	WHERE status in ($1, $2);`
+	return orders, &models.Cursor{Sort: q.Sort, Value: sortValue, ID: string(orders[q.Limit-1].OrderID)}, nil
+}
 
-	rows, err := s.db.QueryContext(ctx, query, models.StatusNew, models.StatusProcessing)
+func (s *StorageDB) ClaimOrderJobs(ctx context.Context, limit int, visibility time.Duration) ([]models.OrderJob, error) {
+	query := `
+	UPDATE accrual_jobs
+	SET locked_until = NOW() + $2 * INTERVAL '1 millisecond', attempts = attempts + 1
+	WHERE order_id IN (
+		SELECT order_id
+		FROM accrual_jobs
+		WHERE NOT dead AND next_run_at <= NOW() AND (locked_until IS NULL OR locked_until <= NOW())
+		ORDER BY next_run_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING order_id, attempts;`
+
+	rows, err := s.db.QueryContext(ctx, query, limit, visibility.Milliseconds())
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var orders []models.OrderID
+	var jobs []models.OrderJob
 
 	for rows.Next() {
-		var order models.OrderID
-		err := rows.Scan(&order)
+		var job models.OrderJob
+		err := rows.Scan(&job.OrderID, &job.Attempts)
 		if err != nil {
 			return nil, err
 		}
-		orders = append(orders, order)
+		jobs = append(jobs, job)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return orders, nil
+	return jobs, nil
 }
 
-func (s *StorageDB) GetUserAccrualBalance(ctx context.Context, userID uuid.UUID) (float32, error) {
+func (s *StorageDB) RescheduleOrderJob(ctx context.Context, orderID models.OrderID, runAt time.Time, lastError string) error {
 	query := `
-	SELECT COALESCE(SUM(accrual), 0) AS total_accrual
-	FROM user_orders
-	WHERE user_id = $1 AND status = $2;`
+	UPDATE accrual_jobs
+	SET next_run_at = $2, locked_until = NULL, last_error = $3
+	WHERE order_id = $1;`
+	_, err := s.db.ExecContext(ctx, query, orderID, runAt, lastError)
 
-	var sum float32
-	err := s.db.QueryRowContext(ctx, query, userID, models.StatusProcessed).Scan(&sum)
-	if err != nil {
-		return -1, err
-	}
+	return err
+}
 
-	return sum, nil
+// KillOrderJob stops retrying the order. The job is kept with its last error
+// until an admin re-check revives it.
+func (s *StorageDB) KillOrderJob(ctx context.Context, orderID models.OrderID, lastError string) error {
+	query := `
+	UPDATE accrual_jobs
+	SET dead = TRUE, locked_until = NULL, last_error = $2
+	WHERE order_id = $1;`
+	_, err := s.db.ExecContext(ctx, query, orderID, lastError)
+
+	return err
 }
 
-func (s *StorageDB) AddWithdrawal(ctx context.Context, userID uuid.UUID, orderID models.OrderID, amount float32) error {
+func (s *StorageDB) FinishOrderJob(ctx context.Context, orderID models.OrderID, status models.OrderStatus, accrual models.Amount) error {
 	tr, errTr := s.db.BeginTx(ctx, nil)
 	if errTr != nil {
 		return errTr
 	}
 
-	querySelect := `
-	SELECT accrual
-	FROM user_orders
-	WHERE user_id = $1 AND status = $2
-	FOR UPDATE;`
+	queryUpdate := `
+	UPDATE user_orders o
+	SET status = $2, accrual = $3,
+		status_changed_at = CASE WHEN o.status IS DISTINCT FROM $2 THEN NOW() ELSE o.status_changed_at END
+	FROM (SELECT status, accrual, status_changed_at FROM user_orders WHERE order_id = $1 FOR UPDATE) old
+	WHERE o.order_id = $1 AND (old.status NOT IN ('PROCESSED', 'INVALID')
+		OR old.status IS DISTINCT FROM $2 OR COALESCE(old.accrual, 0) IS DISTINCT FROM $3::numeric)
+	RETURNING o.user_id, old.status, EXTRACT(EPOCH FROM NOW() - old.status_changed_at)::float8;`
+	// a final order that is checked again with the same result is left as it
+	// is: its accrual is already in the ledger and its events have been sent
+	var userID uuid.UUID
+	var transition orderTransition
+	err := tr.QueryRowContext(ctx, queryUpdate, orderID, status, accrual).Scan(&userID, &transition.from, &transition.seconds)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		tr.Rollback()
+		return err
+	}
+
+	if err == nil {
+		if errEvent := recordOrderEvent(ctx, tr, userID, orderID, status, accrual); errEvent != nil {
+			tr.Rollback()
+			return errEvent
+		}
 
-	rows, err := tr.QueryContext(ctx, querySelect, userID, models.StatusProcessed)
+		eventType := models.WebhookOrderInvalid
+		if status == models.StatusProcessed {
+			eventType = models.WebhookOrderProcessed
+		}
+		errWebhook := enqueueWebhookEvent(ctx, tr, userID, eventType, models.WebhookOrderData{OrderID: orderID, Status: string(status), Accrual: accrual})
+		if errWebhook != nil {
+			tr.Rollback()
+			return errWebhook
+		}
+	}
+
+	updated := err == nil
+	credited := models.Amount(0)
+	if updated {
+		settled, errLedger := settleOrderAccrual(ctx, tr, userID, orderID, status, accrual)
+		if errLedger != nil {
+			tr.Rollback()
+			return errLedger
+		}
+		credited = settled
+	}
+
+	_, err = tr.ExecContext(ctx, `DELETE FROM accrual_jobs WHERE order_id = $1;`, orderID)
 	if err != nil {
 		tr.Rollback()
 		return err
 	}
-	defer rows.Close()
 
-	var balance float32
-	for rows.Next() {
-		var accrual float32
-		if err := rows.Scan(&accrual); err != nil {
-			tr.Rollback()
-			return err
+	if err := tr.Commit(); err != nil {
+		return err
+	}
+
+	if updated {
+		transition.observe(status)
+	}
+	if credited > 0 {
+		metrics.PointsAccrued.Add(credited.Float64())
+	}
+	return nil
+}
+
+func (s *StorageDB) GetUserBalance(ctx context.Context, userID uuid.UUID) (*models.Balance, error) {
+	query := `
+	SELECT current, withdrawn
+	FROM user_balances
+	WHERE user_id = $1;`
+
+	var balance models.Balance
+	err := s.db.QueryRowContext(ctx, query, userID).Scan(&balance.Current, &balance.Withdrawn)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.Balance{}, nil
 		}
-		balance += accrual
+		return nil, err
 	}
 
-	if err := rows.Err(); err != nil {
+	return &balance, nil
+}
+
+func (s *StorageDB) AddWithdrawal(ctx context.Context, userID uuid.UUID, orderID models.OrderID, amount models.Amount) error {
+	tr, errTr := s.db.BeginTx(ctx, nil)
+	if errTr != nil {
+		return errTr
+	}
+
+	balance, err := lockUserBalance(ctx, tr, userID)
+	if err != nil {
 		tr.Rollback()
 		return err
 	}
@@ -258,57 +385,163 @@ func (s *StorageDB) AddWithdrawal(ctx context.Context, userID uuid.UUID, orderID
 
 	queryInsert := `
 	INSERT INTO user_withdrawals (order_id, withdrawal_amount, added_at, user_id)
-    VALUES ($1, $2, NOW(), $3);`
-	_, errInsert := tr.ExecContext(ctx, queryInsert, orderID, amount, userID)
+    VALUES ($1, $2, NOW(), $3)
+	RETURNING added_at;`
+	var processedAt time.Time
+	errInsert := tr.QueryRowContext(ctx, queryInsert, orderID, amount, userID).Scan(&processedAt)
 	if errInsert != nil {
 		tr.Rollback()
+		var pgErr *pgconn.PgError
+		if errors.As(errInsert, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+			return customerror.ErrWithdrawalAlreadyExists
+		}
 		return errInsert
 	}
 
-	return tr.Commit()
-}
+	_, errLedger := postLedgerTransaction(ctx, tr, userID, models.LedgerWithdrawal, -amount, &orderID, "", "")
+	if errLedger != nil {
+		tr.Rollback()
+		return errLedger
+	}
 
-func (s *StorageDB) GetUserWithdrawalSum(ctx context.Context, userID uuid.UUID) (float32, error) {
-	query := `
-	SELECT COALESCE(SUM(withdrawal_amount), 0) AS total_withdrawal
-	FROM user_withdrawals
-	WHERE user_id = $1;`
+	withdrawal := models.Withdrawal{OrderID: orderID, Sum: amount, ProcessedAt: processedAt.UTC()}
+	if err := enqueueWebhookEvent(ctx, tr, userID, models.WebhookWithdrawalSucceeded, withdrawal); err != nil {
+		tr.Rollback()
+		return err
+	}
 
-	var sum float32
-	err := s.db.QueryRowContext(ctx, query, userID).Scan(&sum)
-	if err != nil {
-		return -1, err
+	if err := tr.Commit(); err != nil {
+		return err
 	}
 
-	return sum, nil
+	metrics.PointsWithdrawn.Add(amount.Float64())
+	return nil
 }
 
-func (s *StorageDB) GetUserWithdrawals(ctx context.Context, userID uuid.UUID) ([]models.Withdrawal, error) {
-	query := `SELECT order_id, withdrawal_amount, added_at::text
-	FROM user_withdrawals
-	WHERE user_id = $1
-	ORDER BY added_at DESC;`
+func (s *StorageDB) GetUserWithdrawals(ctx context.Context, userID uuid.UUID, q models.ListQuery) ([]models.Withdrawal, *models.Cursor, error) {
+	b := &listQueryBuilder{}
+	b.where("user_id = " + b.arg(userID))
 
-	rows, err := s.db.QueryContext(ctx, query, userID)
+	query, err := b.build(withdrawalsTable, "withdrawal_amount, added_at", q)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, b.args...)
+	if err != nil {
+		return nil, nil, err
 	}
 	defer rows.Close()
 
 	var withdrawals []models.Withdrawal
+	var sortValue string
 
 	for rows.Next() {
 		var w models.Withdrawal
-		err := rows.Scan(&w.OrderID, &w.Sum, &w.ProcessedAt)
+		var value string
+		err := rows.Scan(&w.Sum, &w.ProcessedAt, &w.OrderID, &value)
 		if err != nil {
-			continue
+			return nil, nil, err
+		}
+		if len(withdrawals) < q.Limit {
+			sortValue = value
 		}
 		withdrawals = append(withdrawals, w)
 	}
 
 	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if q.Limit == 0 || len(withdrawals) <= q.Limit {
+		return withdrawals, nil, nil
+	}
+	withdrawals = withdrawals[:q.Limit]
+
+	return withdrawals, &models.Cursor{Sort: q.Sort, Value: sortValue, ID: string(withdrawals[q.Limit-1].OrderID)}, nil
+}
+
+func (s *StorageDB) BeginIdempotentRequest(ctx context.Context, userID uuid.UUID, key string, requestHash string) (*models.IdempotentResponse, error) {
+	queryReserve := `
+	INSERT INTO idempotency_keys (user_id, idempotency_key, request_hash)
+	VALUES ($1, $2, $3)
+	ON CONFLICT (user_id, idempotency_key) DO UPDATE
+	SET created_at = NOW()
+	WHERE idempotency_keys.status_code IS NULL
+		AND idempotency_keys.request_hash = EXCLUDED.request_hash
+		AND idempotency_keys.created_at < NOW() - INTERVAL '1 minute'
+	RETURNING true;`
+
+	var reserved bool
+	err := s.db.QueryRowContext(ctx, queryReserve, userID, key, requestHash).Scan(&reserved)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
 		return nil, err
 	}
 
-	return withdrawals, nil
+	querySelect := `
+	SELECT request_hash, status_code, response_body
+	FROM idempotency_keys
+	WHERE user_id = $1 AND idempotency_key = $2;`
+
+	var storedHash string
+	var statusCode sql.NullInt32
+	var body []byte
+	err = s.db.QueryRowContext(ctx, querySelect, userID, key).Scan(&storedHash, &statusCode, &body)
+	if err != nil {
+		return nil, err
+	}
+
+	if storedHash != requestHash {
+		return nil, customerror.ErrIdempotencyKeyMismatch
+	}
+	if !statusCode.Valid {
+		return nil, customerror.ErrIdempotencyKeyInProgress
+	}
+
+	return &models.IdempotentResponse{StatusCode: int(statusCode.Int32), Body: body}, nil
+}
+
+func (s *StorageDB) CompleteIdempotentRequest(ctx context.Context, userID uuid.UUID, key string, response models.IdempotentResponse) error {
+	query := `
+	UPDATE idempotency_keys
+	SET status_code = $3, response_body = $4
+	WHERE user_id = $1 AND idempotency_key = $2;`
+	_, err := s.db.ExecContext(ctx, query, userID, key, response.StatusCode, response.Body)
+
+	return err
+}
+
+func (s *StorageDB) ReleaseIdempotentRequest(ctx context.Context, userID uuid.UUID, key string) error {
+	query := `
+	DELETE FROM idempotency_keys
+	WHERE user_id = $1 AND idempotency_key = $2 AND status_code IS NULL;`
+	_, err := s.db.ExecContext(ctx, query, userID, key)
+
+	return err
+}
+
+// orderTransition is the status an order left and how long it had been in
+// it.
+type orderTransition struct {
+	from    string
+	seconds float64
+}
+
+func (t orderTransition) observe(to models.OrderStatus) {
+	if t.from != string(to) {
+		metrics.OrderStateDuration.WithLabelValues(t.from).Observe(t.seconds)
+	}
+}
+
+func (s *StorageDB) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *StorageDB) CountOrderJobs(ctx context.Context) (int, error) {
+	var count int
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM accrual_jobs WHERE NOT dead;`).Scan(&count)
+	return count, err
 }
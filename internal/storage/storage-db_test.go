@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/require"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+// openTestStorage migrates the database at TEST_DATABASE_URI; the tests that
+// need it are skipped without one.
+func openTestStorage(t *testing.T) *StorageDB {
+	t.Helper()
+	uri := os.Getenv("TEST_DATABASE_URI")
+	if uri == "" {
+		t.Skip("TEST_DATABASE_URI is not set")
+	}
+
+	db, err := sql.Open("pgx", uri)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	ctx := context.Background()
+	require.NoError(t, MigrateUp(ctx, db))
+	s, err := NewStorageDB(ctx, db)
+	require.NoError(t, err)
+	return s
+}
+
+func createTestOrder(t *testing.T, s *StorageDB) (uuid.UUID, models.OrderID) {
+	t.Helper()
+	ctx := context.Background()
+
+	login := "test-" + uuid.NewString()
+	require.NoError(t, s.CreateUser(ctx, login, "hash"))
+	userID, err := s.GetUserID(ctx, login)
+	require.NoError(t, err)
+
+	orderID := models.OrderID(strconv.FormatInt(time.Now().UnixNano(), 10))
+	require.NoError(t, s.AddOrder(ctx, userID, models.StatusNew, orderID))
+	return userID, orderID
+}
+
+func TestUpdateOrderStatus_LeavesFinalOrders(t *testing.T) {
+	s := openTestStorage(t)
+	ctx := context.Background()
+	userID, orderID := createTestOrder(t, s)
+
+	require.NoError(t, s.FinishOrderJob(ctx, orderID, models.StatusProcessed, 50000))
+	eventsBefore, err := s.GetOrderEvents(ctx, userID, 0, 100)
+	require.NoError(t, err)
+
+	// a worker whose lease ran out reports the order as still processing
+	require.NoError(t, s.UpdateOrderStatus(ctx, orderID, models.StatusProcessing))
+
+	orders, _, err := s.GetUserOrders(ctx, userID, models.ListQuery{Sort: "-uploaded_at"})
+	require.NoError(t, err)
+	require.Len(t, orders, 1)
+	require.Equal(t, string(models.StatusProcessed), orders[0].Status)
+	require.Equal(t, models.Amount(50000), orders[0].Accrual)
+
+	eventsAfter, err := s.GetOrderEvents(ctx, userID, 0, 100)
+	require.NoError(t, err)
+	require.Equal(t, eventsBefore, eventsAfter)
+
+	balance, err := s.GetUserBalance(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, models.Amount(50000), balance.Current)
+}
+
+func TestFinishOrderJob_RecheckSettlesLedger(t *testing.T) {
+	s := openTestStorage(t)
+	ctx := context.Background()
+	userID, orderID := createTestOrder(t, s)
+
+	requireBalance := func(want models.Amount) {
+		t.Helper()
+		balance, err := s.GetUserBalance(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, want, balance.Current)
+	}
+
+	require.NoError(t, s.FinishOrderJob(ctx, orderID, models.StatusProcessed, 50000))
+	requireBalance(50000)
+	eventsBefore, err := s.GetOrderEvents(ctx, userID, 0, 100)
+	require.NoError(t, err)
+
+	// the same result again neither credits nor notifies
+	require.NoError(t, s.RequeueOrderJob(ctx, orderID))
+	require.NoError(t, s.FinishOrderJob(ctx, orderID, models.StatusProcessed, 50000))
+	requireBalance(50000)
+	eventsAfter, err := s.GetOrderEvents(ctx, userID, 0, 100)
+	require.NoError(t, err)
+	require.Equal(t, eventsBefore, eventsAfter)
+
+	require.NoError(t, s.RequeueOrderJob(ctx, orderID))
+	require.NoError(t, s.FinishOrderJob(ctx, orderID, models.StatusProcessed, 30000))
+	requireBalance(30000)
+
+	require.NoError(t, s.RequeueOrderJob(ctx, orderID))
+	require.NoError(t, s.FinishOrderJob(ctx, orderID, models.StatusInvalid, 0))
+	requireBalance(0)
+
+	require.NoError(t, s.RequeueOrderJob(ctx, orderID))
+	require.NoError(t, s.FinishOrderJob(ctx, orderID, models.StatusProcessed, 50000))
+	requireBalance(50000)
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/with0p/gophermart/internal/models"
@@ -9,15 +10,43 @@ import (
 
 type Storage interface {
 	CreateUser(ctx context.Context, login, password string) error
-	ValidateUser(ctx context.Context, login, password string) error
+	GetUserPasswordHash(ctx context.Context, login string) (string, error)
+	UpdateUserPasswordHash(ctx context.Context, login, oldHash, newHash string) error
 	GetUserID(ctx context.Context, login string) (uuid.UUID, error)
 	GetOrder(ctx context.Context, orderID models.OrderID) (*models.Order, error)
 	AddOrder(ctx context.Context, userID uuid.UUID, status models.OrderStatus, orderID models.OrderID) error
-	UpdateOrder(ctx context.Context, orderID models.OrderID, status models.OrderStatus, accrual float32) error
-	GetUserOrders(ctx context.Context, userID uuid.UUID) ([]models.Order, error)
-	GetUnfinishedOrderIDs(ctx context.Context) ([]models.OrderID, error)
-	GetUserAccrualBalance(ctx context.Context, userID uuid.UUID) (float32, error)
-	AddWithdrawal(ctx context.Context, userID uuid.UUID, orderID models.OrderID, amount float32) error
-	GetUserWithdrawalSum(ctx context.Context, userID uuid.UUID) (float32, error)
-	GetUserWithdrawals(ctx context.Context, userID uuid.UUID) ([]models.Withdrawal, error)
+	AddOrders(ctx context.Context, userID uuid.UUID, status models.OrderStatus, orderIDs []models.OrderID) ([]models.OrderUploadResult, error)
+	UpdateOrderStatus(ctx context.Context, orderID models.OrderID, status models.OrderStatus) error
+	GetUserOrders(ctx context.Context, userID uuid.UUID, q models.ListQuery) ([]models.Order, *models.Cursor, error)
+	GetOrderEvents(ctx context.Context, userID uuid.UUID, afterID int64, limit int) ([]models.OrderEvent, error)
+	PruneOrderEvents(ctx context.Context, before time.Time) error
+	ListenOrderEvents(ctx context.Context, handle func(models.OrderEvent)) error
+	ClaimOrderJobs(ctx context.Context, limit int, visibility time.Duration) ([]models.OrderJob, error)
+	RescheduleOrderJob(ctx context.Context, orderID models.OrderID, runAt time.Time, lastError string) error
+	KillOrderJob(ctx context.Context, orderID models.OrderID, lastError string) error
+	FinishOrderJob(ctx context.Context, orderID models.OrderID, status models.OrderStatus, accrual models.Amount) error
+	GetUserBalance(ctx context.Context, userID uuid.UUID) (*models.Balance, error)
+	AddWithdrawal(ctx context.Context, userID uuid.UUID, orderID models.OrderID, amount models.Amount) error
+	GetUserWithdrawals(ctx context.Context, userID uuid.UUID, q models.ListQuery) ([]models.Withdrawal, *models.Cursor, error)
+	BeginIdempotentRequest(ctx context.Context, userID uuid.UUID, key string, requestHash string) (*models.IdempotentResponse, error)
+	CompleteIdempotentRequest(ctx context.Context, userID uuid.UUID, key string, response models.IdempotentResponse) error
+	ReleaseIdempotentRequest(ctx context.Context, userID uuid.UUID, key string) error
+	GetUserPreferences(ctx context.Context, userID uuid.UUID) (*models.UserPreferences, error)
+	SetUserPreferences(ctx context.Context, userID uuid.UUID, preferences models.UserPreferences) error
+	CreateWebhookEndpoint(ctx context.Context, userID uuid.UUID, endpoint models.WebhookEndpoint) (*models.WebhookEndpoint, error)
+	GetWebhookEndpoints(ctx context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error)
+	DeleteWebhookEndpoint(ctx context.Context, userID uuid.UUID, endpointID uuid.UUID) error
+	GetWebhookDeliveries(ctx context.Context, userID uuid.UUID, endpointID uuid.UUID, limit int) ([]models.WebhookDelivery, error)
+	ReplayWebhookDelivery(ctx context.Context, userID uuid.UUID, deliveryID int64) error
+	ClaimWebhookDeliveries(ctx context.Context, limit int, visibility time.Duration) ([]models.WebhookDelivery, error)
+	FinishWebhookDelivery(ctx context.Context, deliveryID int64, status models.WebhookDeliveryStatus, runAt time.Time, statusCode int, lastError string) error
+	ListUsers(ctx context.Context, search string, limit int, offset int) ([]models.UserInfo, error)
+	GetUserInfo(ctx context.Context, login string) (*models.UserInfo, error)
+	SetUserRoles(ctx context.Context, login string, roles []string) error
+	SetUserLocked(ctx context.Context, login string, locked bool, reason string) error
+	AdjustBalance(ctx context.Context, userID uuid.UUID, amount models.Amount, reason string, createdBy string) error
+	GetUserLedger(ctx context.Context, userID uuid.UUID) ([]models.LedgerEntry, error)
+	RequeueOrderJob(ctx context.Context, orderID models.OrderID) error
+	CountOrderJobs(ctx context.Context) (int, error)
+	Ping(ctx context.Context) error
 }
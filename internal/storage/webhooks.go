@@ -0,0 +1,245 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func joinWebhookEventTypes(eventTypes []models.WebhookEventType) string {
+	values := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		values[i] = string(eventType)
+	}
+	return strings.Join(values, ",")
+}
+
+func splitWebhookEventTypes(value string) []models.WebhookEventType {
+	eventTypes := []models.WebhookEventType{}
+	for _, eventType := range splitRoles(value) {
+		eventTypes = append(eventTypes, models.WebhookEventType(eventType))
+	}
+	return eventTypes
+}
+
+// enqueueWebhookEvent queues a delivery of the event to every endpoint of
+// the user subscribed to its type. It runs in the transaction that produced
+// the event, so deliveries are never lost or sent for rolled back changes.
+func enqueueWebhookEvent(ctx context.Context, tr *sql.Tx, userID uuid.UUID, eventType models.WebhookEventType, data any) error {
+	payload, err := json.Marshal(models.WebhookEvent{
+		ID:        uuid.New(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	query := `
+	INSERT INTO webhook_deliveries (endpoint_id, event_type, payload)
+	SELECT id, $2, $3
+	FROM webhook_endpoints
+	WHERE user_id = $1 AND $2 = ANY(event_types);`
+	_, err = tr.ExecContext(ctx, query, userID, eventType, string(payload))
+	return err
+}
+
+func (s *StorageDB) CreateWebhookEndpoint(ctx context.Context, userID uuid.UUID, endpoint models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	query := `
+	INSERT INTO webhook_endpoints (user_id, url, secret, event_types)
+	VALUES ($1, $2, $3, string_to_array($4, ','))
+	RETURNING id, created_at;`
+
+	err := s.db.QueryRowContext(ctx, query, userID, endpoint.URL, endpoint.Secret, joinWebhookEventTypes(endpoint.EventTypes)).
+		Scan(&endpoint.ID, &endpoint.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &endpoint, nil
+}
+
+func (s *StorageDB) GetWebhookEndpoints(ctx context.Context, userID uuid.UUID) ([]models.WebhookEndpoint, error) {
+	query := `
+	SELECT id, url, array_to_string(event_types, ','), created_at
+	FROM webhook_endpoints
+	WHERE user_id = $1
+	ORDER BY created_at;`
+
+	rows, err := s.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	endpoints := []models.WebhookEndpoint{}
+
+	for rows.Next() {
+		var endpoint models.WebhookEndpoint
+		var eventTypes string
+		err := rows.Scan(&endpoint.ID, &endpoint.URL, &eventTypes, &endpoint.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		endpoint.EventTypes = splitWebhookEventTypes(eventTypes)
+		endpoints = append(endpoints, endpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return endpoints, nil
+}
+
+func (s *StorageDB) DeleteWebhookEndpoint(ctx context.Context, userID uuid.UUID, endpointID uuid.UUID) error {
+	query := `DELETE FROM webhook_endpoints WHERE id = $1 AND user_id = $2;`
+
+	result, err := s.db.ExecContext(ctx, query, endpointID, userID)
+	if err != nil {
+		return err
+	}
+
+	return expectOneRow(result, customerror.ErrNoSuchWebhook)
+}
+
+func (s *StorageDB) GetWebhookDeliveries(ctx context.Context, userID uuid.UUID, endpointID uuid.UUID, limit int) ([]models.WebhookDelivery, error) {
+	var exists bool
+	queryEndpoint := `SELECT EXISTS (SELECT 1 FROM webhook_endpoints WHERE id = $1 AND user_id = $2);`
+	if err := s.db.QueryRowContext(ctx, queryEndpoint, endpointID, userID).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, customerror.ErrNoSuchWebhook
+	}
+
+	query := `
+	SELECT id, endpoint_id, event_type, payload::text, status, attempts, COALESCE(last_status_code, 0),
+		COALESCE(last_error, ''), next_run_at, created_at, delivered_at
+	FROM webhook_deliveries
+	WHERE endpoint_id = $1
+	ORDER BY id DESC
+	LIMIT $2;`
+
+	rows, err := s.db.QueryContext(ctx, query, endpointID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+
+	for rows.Next() {
+		var delivery models.WebhookDelivery
+		var payload string
+		err := rows.Scan(&delivery.ID, &delivery.EndpointID, &delivery.EventType, &payload, &delivery.Status, &delivery.Attempts,
+			&delivery.LastStatusCode, &delivery.LastError, &delivery.NextRunAt, &delivery.CreatedAt, &delivery.DeliveredAt)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Payload = json.RawMessage(payload)
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// ReplayWebhookDelivery sends a delivered or dead delivery again, starting
+// over with a fresh attempt count. Pending deliveries are still being tried
+// and are refused.
+func (s *StorageDB) ReplayWebhookDelivery(ctx context.Context, userID uuid.UUID, deliveryID int64) error {
+	query := `
+	UPDATE webhook_deliveries d
+	SET status = 'pending', attempts = 0, next_run_at = NOW(), locked_until = NULL
+	FROM webhook_endpoints e
+	WHERE d.id = $1 AND d.endpoint_id = e.id AND e.user_id = $2 AND d.status IN ('delivered', 'dead');`
+
+	result, err := s.db.ExecContext(ctx, query, deliveryID, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil || affected > 0 {
+		return err
+	}
+
+	queryExists := `
+	SELECT EXISTS (
+		SELECT 1 FROM webhook_deliveries d JOIN webhook_endpoints e ON e.id = d.endpoint_id
+		WHERE d.id = $1 AND e.user_id = $2
+	);`
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, queryExists, deliveryID, userID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return customerror.ErrNoSuchWebhookDelivery
+	}
+	return customerror.ErrWebhookDeliveryPending
+}
+
+func (s *StorageDB) ClaimWebhookDeliveries(ctx context.Context, limit int, visibility time.Duration) ([]models.WebhookDelivery, error) {
+	query := `
+	UPDATE webhook_deliveries d
+	SET locked_until = NOW() + $2 * INTERVAL '1 millisecond', attempts = d.attempts + 1
+	FROM webhook_endpoints e
+	WHERE e.id = d.endpoint_id AND d.id IN (
+		SELECT id
+		FROM webhook_deliveries
+		WHERE status = 'pending' AND next_run_at <= NOW() AND (locked_until IS NULL OR locked_until <= NOW())
+		ORDER BY next_run_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING d.id, d.endpoint_id, d.event_type, d.payload::text, d.attempts, d.created_at, e.url, e.secret;`
+
+	rows, err := s.db.QueryContext(ctx, query, limit, visibility.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+
+	for rows.Next() {
+		delivery := models.WebhookDelivery{Status: models.WebhookPending}
+		var payload string
+		err := rows.Scan(&delivery.ID, &delivery.EndpointID, &delivery.EventType, &payload, &delivery.Attempts,
+			&delivery.CreatedAt, &delivery.URL, &delivery.Secret)
+		if err != nil {
+			return nil, err
+		}
+		delivery.Payload = json.RawMessage(payload)
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// FinishWebhookDelivery records a delivery attempt. A pending status with
+// runAt schedules a retry; delivered and dead are final.
+func (s *StorageDB) FinishWebhookDelivery(ctx context.Context, deliveryID int64, status models.WebhookDeliveryStatus, runAt time.Time, statusCode int, lastError string) error {
+	query := `
+	UPDATE webhook_deliveries
+	SET status = $2, next_run_at = $3, locked_until = NULL, last_status_code = NULLIF($4, 0), last_error = NULLIF($5, ''),
+		delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() END
+	WHERE id = $1;`
+
+	_, err := s.db.ExecContext(ctx, query, deliveryID, status, runAt, statusCode, lastError)
+	return err
+}
@@ -0,0 +1,51 @@
+package supervisor
+
+import (
+	"bytes"
+	"io"
+)
+
+// prefixWriter writes every complete line it is given to out, prefixed.
+// It is not safe for concurrent use; exec.Cmd writes to it from one goroutine
+// when Stdout and Stderr are the same writer.
+type prefixWriter struct {
+	out     io.Writer
+	prefix  []byte
+	pending []byte
+}
+
+func newPrefixWriter(out io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{out: out, prefix: []byte(prefix)}
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.pending[:i+1]); err != nil {
+			return 0, err
+		}
+		w.pending = w.pending[i+1:]
+	}
+}
+
+// Flush writes out a trailing line that has no newline.
+func (w *prefixWriter) Flush() error {
+	if len(w.pending) == 0 {
+		return nil
+	}
+	line := append(w.pending, '\n')
+	w.pending = nil
+	return w.writeLine(line)
+}
+
+func (w *prefixWriter) writeLine(line []byte) error {
+	buf := make([]byte, 0, len(w.prefix)+len(line))
+	buf = append(buf, w.prefix...)
+	buf = append(buf, line...)
+	_, err := w.out.Write(buf)
+	return err
+}
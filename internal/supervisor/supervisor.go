@@ -0,0 +1,102 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/with0p/gophermart/internal/logger"
+)
+
+const (
+	defaultMinBackoff  = time.Second
+	defaultMaxBackoff  = 30 * time.Second
+	defaultStopTimeout = 5 * time.Second
+	// a process that ran this long is considered healthy again, so the next
+	// restart starts over from the minimum backoff
+	stableRunTime = time.Minute
+)
+
+var errExited = errors.New("exited")
+
+type Config struct {
+	// Name prefixes every output line of the process.
+	Name string
+	Path string
+	Args []string
+	// Output receives the prefixed stdout and stderr, os.Stdout by default.
+	Output      io.Writer
+	MinBackoff  time.Duration
+	MaxBackoff  time.Duration
+	StopTimeout time.Duration
+}
+
+// Run keeps the process running until ctx is done, restarting it with a
+// growing delay whenever it exits. On cancellation the process gets an
+// interrupt and is killed if it has not exited within StopTimeout.
+func Run(ctx context.Context, conf Config) {
+	if conf.Output == nil {
+		conf.Output = os.Stdout
+	}
+	if conf.MinBackoff <= 0 {
+		conf.MinBackoff = defaultMinBackoff
+	}
+	if conf.MaxBackoff <= 0 {
+		conf.MaxBackoff = defaultMaxBackoff
+	}
+	if conf.StopTimeout <= 0 {
+		conf.StopTimeout = defaultStopTimeout
+	}
+
+	delay := conf.MinBackoff
+	for {
+		started := time.Now()
+		err := runOnce(ctx, conf)
+		if ctx.Err() != nil {
+			logger.Info("Process stopped", "process", conf.Name)
+			return
+		}
+
+		if time.Since(started) >= stableRunTime {
+			delay = conf.MinBackoff
+		}
+		logger.Error(err, "process", conf.Name, "restart_in", delay.String())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > conf.MaxBackoff {
+			delay = conf.MaxBackoff
+		}
+	}
+}
+
+func runOnce(ctx context.Context, conf Config) error {
+	output := newPrefixWriter(conf.Output, "["+conf.Name+"] ")
+	defer output.Flush()
+
+	cmd := exec.CommandContext(ctx, conf.Path, conf.Args...)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(os.Interrupt)
+	}
+	cmd.WaitDelay = conf.StopTimeout
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	logger.Info("Process started", "process", conf.Name, "pid", cmd.Process.Pid)
+
+	if err := cmd.Wait(); err != nil {
+		return err
+	}
+	return errExited
+}
@@ -0,0 +1,91 @@
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := newPrefixWriter(&out, "[accrual] ")
+
+	w.Write([]byte("listening on "))
+	w.Write([]byte(":8081\nready\npartial"))
+	assert.Equal(t, "[accrual] listening on :8081\n[accrual] ready\n", out.String())
+
+	w.Flush()
+	assert.Equal(t, "[accrual] listening on :8081\n[accrual] ready\n[accrual] partial\n", out.String())
+}
+
+func TestRun_RestartsUntilCancelled(t *testing.T) {
+	var out syncBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Run(ctx, Config{
+			Name:       "echo",
+			Path:       "/bin/sh",
+			Args:       []string{"-c", "echo started; exit 1"},
+			Output:     &out,
+			MinBackoff: 10 * time.Millisecond,
+		})
+	}()
+
+	assert.Eventually(t, func() bool {
+		return strings.Count(out.String(), "[echo] started\n") >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+}
+
+func TestRun_StopsProcessOnCancel(t *testing.T) {
+	var out syncBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		Run(ctx, Config{
+			Name:        "sleep",
+			Path:        "/bin/sh",
+			Args:        []string{"-c", "echo started; exec sleep 30"},
+			Output:      &out,
+			StopTimeout: time.Second,
+		})
+	}()
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(out.String(), "[sleep] started\n")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("process was not stopped")
+	}
+}
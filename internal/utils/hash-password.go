@@ -1,13 +0,0 @@
-package utils
-
-import (
-	"crypto/sha256"
-	"encoding/hex"
-)
-
-func HashPassword(password string) string {
-	hash := sha256.New()
-	hash.Write([]byte(password))
-	hashedPassword := hash.Sum(nil)
-	return hex.EncodeToString(hashedPassword)
-}
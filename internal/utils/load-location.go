@@ -0,0 +1,26 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+)
+
+var locations sync.Map
+
+// LoadLocation is time.LoadLocation with a cache, since responses convert
+// every row into the client's zone.
+func LoadLocation(name string) (*time.Location, error) {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, customerror.ErrUnknownTimeZone
+	}
+
+	locations.Store(name, loc)
+	return loc, nil
+}
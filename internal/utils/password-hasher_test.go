@@ -0,0 +1,60 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgon2idHasher_HashAndVerify(t *testing.T) {
+	hasher := NewArgon2idHasher()
+
+	encoded, err := hasher.Hash("password1")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$"))
+
+	ok, needsRehash, err := hasher.Verify("password1", encoded)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, needsRehash)
+
+	ok, _, err = hasher.Verify("password2", encoded)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestArgon2idHasher_VerifyLegacy(t *testing.T) {
+	hasher := NewArgon2idHasher()
+	sum := sha256.Sum256([]byte("password1"))
+	legacy := hex.EncodeToString(sum[:])
+
+	ok, needsRehash, err := hasher.Verify("password1", legacy)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, needsRehash)
+
+	ok, _, err = hasher.Verify("password2", legacy)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestArgon2idHasher_RehashOnParamsChange(t *testing.T) {
+	weak := NewArgon2idHasher()
+	weak.Iterations = 1
+	encoded, err := weak.Hash("password1")
+	assert.NoError(t, err)
+
+	ok, needsRehash, err := NewArgon2idHasher().Verify("password1", encoded)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, needsRehash)
+}
+
+func TestArgon2idHasher_UnknownFormat(t *testing.T) {
+	_, _, err := NewArgon2idHasher().Verify("password1", "$2a$10$abcdef")
+
+	assert.ErrorIs(t, err, ErrUnknownPasswordHash)
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type RateLimiter struct {
+	mu          sync.Mutex
+	pausedUntil time.Time
+	interval    time.Duration
+	next        time.Time
+}
+
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{}
+}
+
+// Wait blocks until the next request is allowed: after any active pause and
+// no sooner than the advertised rate permits. A slot is only reserved once
+// the pause is over, so waiters woken by an extended pause do not use up
+// slots they never take.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		pausedUntil := l.PausedUntil()
+		if !time.Now().Before(pausedUntil) {
+			break
+		}
+		if err := sleep(ctx, time.Until(pausedUntil)); err != nil {
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := now
+	if l.interval > 0 {
+		if l.next.After(start) {
+			start = l.next
+		}
+		l.next = start.Add(l.interval)
+	}
+	l.mu.Unlock()
+
+	return sleep(ctx, start.Sub(now))
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Pause stops all callers of Wait until the given time. A positive
+// requestsPerMinute limits the rate they resume at.
+func (l *RateLimiter) Pause(until time.Time, requestsPerMinute int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	if requestsPerMinute > 0 {
+		l.interval = time.Minute / time.Duration(requestsPerMinute)
+	}
+}
+
+func (l *RateLimiter) PausedUntil() time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.pausedUntil
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Unlimited(t *testing.T) {
+	l := NewRateLimiter()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, l.Wait(context.Background()))
+	}
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+}
+
+func TestRateLimiter_SpacesRequests(t *testing.T) {
+	l := NewRateLimiter()
+	l.Pause(time.Now(), 1200) // one request per 50ms
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, l.Wait(context.Background()))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+}
+
+func TestRateLimiter_PauseOnlyExtends(t *testing.T) {
+	l := NewRateLimiter()
+	later := time.Now().Add(2 * time.Hour)
+
+	l.Pause(later, 0)
+	l.Pause(time.Now().Add(time.Hour), 0)
+	assert.Equal(t, later, l.PausedUntil())
+}
+
+func TestRateLimiter_ReservesAfterExtendedPause(t *testing.T) {
+	l := NewRateLimiter()
+	start := time.Now()
+	l.Pause(start.Add(30*time.Millisecond), 300) // one request per 200ms
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		l.Pause(start.Add(80*time.Millisecond), 300)
+	}()
+
+	require.NoError(t, l.Wait(context.Background()))
+	elapsed := time.Since(start)
+	assert.GreaterOrEqual(t, elapsed, 80*time.Millisecond)
+	// a slot reserved before the pause was extended would push this to 230ms
+	assert.Less(t, elapsed, 180*time.Millisecond)
+}
+
+func TestRateLimiter_ContextCanceled(t *testing.T) {
+	l := NewRateLimiter()
+	l.Pause(time.Now().Add(time.Hour), 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+}
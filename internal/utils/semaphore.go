@@ -1,19 +0,0 @@
-package utils
-
-type Semaphore struct {
-	semaCh chan struct{}
-}
-
-func NewSemaphore(maxReq int) *Semaphore {
-	return &Semaphore{
-		semaCh: make(chan struct{}, maxReq),
-	}
-}
-
-func (s *Semaphore) Acquire() {
-	s.semaCh <- struct{}{}
-}
-
-func (s *Semaphore) Release() {
-	<-s.semaCh
-}
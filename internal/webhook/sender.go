@@ -0,0 +1,123 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+const (
+	SignatureHeader = "X-Gophermart-Signature"
+	EventHeader     = "X-Gophermart-Event"
+	DeliveryHeader  = "X-Gophermart-Delivery"
+
+	defaultTimeout = 10 * time.Second
+)
+
+type Sender interface {
+	// Send returns the status code of the endpoint, or 0 when no response
+	// was received.
+	Send(ctx context.Context, delivery models.WebhookDelivery) (int, error)
+}
+
+type SenderConfig struct {
+	Timeout time.Duration
+	// Transport replaces the default one, which refuses to connect to
+	// private, loopback and link-local addresses.
+	Transport http.RoundTripper
+}
+
+type HTTPSender struct {
+	client *http.Client
+}
+
+func NewHTTPSender(config SenderConfig) *HTTPSender {
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	transport := config.Transport
+	if transport == nil {
+		transport = newTransport()
+	}
+
+	return &HTTPSender{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+			// a redirect is treated as a failed delivery rather than followed
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// newTransport is the default transport without proxies, which would be
+// dialed instead of the endpoint and so bypass the address check.
+func newTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   dialControl,
+	}).DialContext
+	return transport
+}
+
+func (s *HTTPSender) Send(ctx context.Context, delivery models.WebhookDelivery) (int, error) {
+	// endpoints registered before https was required are not sent to; the
+	// target address is checked by the dialer
+	if parsed, err := url.Parse(delivery.URL); err != nil || parsed.Scheme != "https" {
+		return 0, customerror.ErrInvalidWebhookURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("user-agent", "gophermart-webhooks")
+	req.Header.Set(EventHeader, string(delivery.EventType))
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, fmt.Sprintf("t=%d,v1=%s", timestamp, Sign(delivery.Secret, timestamp, delivery.Payload)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint responded with %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// Sign computes the hex HMAC-SHA256 of "<timestamp>.<body>". Receivers
+// recompute it with their secret and reject stale timestamps to prevent
+// replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
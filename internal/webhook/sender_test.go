@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+	"github.com/with0p/gophermart/internal/models"
+)
+
+func TestHTTPSender_Signature(t *testing.T) {
+	payload := []byte(`{"type":"order.processed"}`)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, payload, body)
+		assert.Equal(t, "order.processed", r.Header.Get(EventHeader))
+		assert.Equal(t, "7", r.Header.Get(DeliveryHeader))
+
+		var timestamp int64
+		var signature string
+		_, err := fmt.Sscanf(r.Header.Get(SignatureHeader), "t=%d,v1=%s", &timestamp, &signature)
+		require.NoError(t, err)
+		assert.Equal(t, Sign("whsec_test", timestamp, body), signature)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	status, err := NewHTTPSender(SenderConfig{Transport: server.Client().Transport}).Send(context.Background(), models.WebhookDelivery{
+		ID: 7, EventType: models.WebhookOrderProcessed, Payload: payload, URL: server.URL, Secret: "whsec_test",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+}
+
+func TestHTTPSender_Failure(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://example.com", http.StatusFound)
+	}))
+	defer server.Close()
+
+	status, err := NewHTTPSender(SenderConfig{Transport: server.Client().Transport}).Send(context.Background(), models.WebhookDelivery{URL: server.URL, Payload: []byte(`{}`)})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusFound, status)
+}
+
+func TestHTTPSender_RefusesInternalTargets(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("internal endpoint was reached")
+	}))
+	defer server.Close()
+
+	status, err := NewHTTPSender(SenderConfig{}).Send(context.Background(), models.WebhookDelivery{URL: server.URL, Payload: []byte(`{}`)})
+	assert.ErrorIs(t, err, customerror.ErrForbiddenWebhookTarget)
+	assert.Equal(t, 0, status)
+}
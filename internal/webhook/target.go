@@ -0,0 +1,68 @@
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+)
+
+// blockedPrefixes are ranges not covered by the netip predicates that still
+// must not be reachable from user-supplied URLs.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// ValidateURL accepts absolute https URLs whose host is not a private,
+// loopback or link-local address. Host names are resolved only when
+// connecting, where the sender checks the address again.
+func ValidateURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "https" || parsed.Hostname() == "" {
+		return customerror.ErrInvalidWebhookURL
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return customerror.ErrForbiddenWebhookTarget
+	}
+	if addr, err := netip.ParseAddr(host); err == nil && !allowedAddr(addr) {
+		return customerror.ErrForbiddenWebhookTarget
+	}
+	return nil
+}
+
+func allowedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialControl runs after name resolution, for every address actually dialed,
+// so a host name that later resolves to an internal address is still refused.
+func dialControl(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !allowedAddr(addr) {
+		return fmt.Errorf("%w: %s", customerror.ErrForbiddenWebhookTarget, addr)
+	}
+	return nil
+}
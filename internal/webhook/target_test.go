@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	customerror "github.com/with0p/gophermart/internal/custom-error"
+)
+
+func TestValidateURL(t *testing.T) {
+	for raw, want := range map[string]error{
+		"https://example.com/hook":         nil,
+		"https://93.184.216.34/hook":       nil,
+		"http://example.com/hook":          customerror.ErrInvalidWebhookURL,
+		"ftp://example.com/hook":           customerror.ErrInvalidWebhookURL,
+		"https:///hook":                    customerror.ErrInvalidWebhookURL,
+		"https://localhost/hook":           customerror.ErrForbiddenWebhookTarget,
+		"https://api.localhost./hook":      customerror.ErrForbiddenWebhookTarget,
+		"https://127.0.0.1/hook":           customerror.ErrForbiddenWebhookTarget,
+		"https://169.254.169.254/latest":   customerror.ErrForbiddenWebhookTarget,
+		"https://10.1.2.3/hook":            customerror.ErrForbiddenWebhookTarget,
+		"https://172.16.0.1/hook":          customerror.ErrForbiddenWebhookTarget,
+		"https://192.168.0.1/hook":         customerror.ErrForbiddenWebhookTarget,
+		"https://100.64.0.1/hook":          customerror.ErrForbiddenWebhookTarget,
+		"https://0.0.0.0/hook":             customerror.ErrForbiddenWebhookTarget,
+		"https://[::1]/hook":               customerror.ErrForbiddenWebhookTarget,
+		"https://[fe80::1]/hook":           customerror.ErrForbiddenWebhookTarget,
+		"https://[fd00::1]/hook":           customerror.ErrForbiddenWebhookTarget,
+		"https://[::ffff:127.0.0.1]/hook":  customerror.ErrForbiddenWebhookTarget,
+		"https://[2606:2800:220:1::]/hook": nil,
+	} {
+		assert.ErrorIs(t, ValidateURL(raw), want, raw)
+	}
+}
+
+func TestDialControl(t *testing.T) {
+	assert.NoError(t, dialControl("tcp4", "93.184.216.34:443", nil))
+	assert.ErrorIs(t, dialControl("tcp4", "127.0.0.1:443", nil), customerror.ErrForbiddenWebhookTarget)
+	assert.ErrorIs(t, dialControl("tcp4", "169.254.169.254:80", nil), customerror.ErrForbiddenWebhookTarget)
+	assert.ErrorIs(t, dialControl("tcp6", "[::1]:443", nil), customerror.ErrForbiddenWebhookTarget)
+}